@@ -0,0 +1,32 @@
+// Package clients provides the Client abstraction ScraperService's Fetcher
+// implementations fetch raw page bodies through, so the parsing logic in
+// the services package (parseProductPage, parseReviewBlocks,
+// parseSearchResults, ...) can be exercised against fixture HTML instead of
+// a live network call.
+package clients
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// Client fetches the raw body for u. HttpClient hits the real network;
+// MockClient and RecordReplayClient serve canned HTML so callers can be
+// tested or run in CI without depending on Amazon being reachable.
+type Client interface {
+	Get(u *url.URL) (io.Reader, error)
+}
+
+// StatusError is returned by HttpClient.Get when the upstream server
+// responds with anything but 200 OK, so callers that care (e.g. the
+// bulk-scrape retry loop deciding whether a 5xx/429 is worth retrying) can
+// recover the status code with errors.As instead of parsing error text.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d for %s", e.StatusCode, e.URL)
+}
@@ -0,0 +1,55 @@
+package clients
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// HttpClient is the default Client: a single GET through httpClient, with
+// headers applied to every request.
+type HttpClient struct {
+	httpClient *http.Client
+	headers    http.Header
+}
+
+// NewHTTPClient creates an HttpClient. If httpClient is nil, http.DefaultClient
+// is used.
+func NewHTTPClient(httpClient *http.Client, headers http.Header) *HttpClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &HttpClient{httpClient: httpClient, headers: headers}
+}
+
+// Get issues a GET for u and returns its body.
+func (c *HttpClient) Get(u *url.URL) (io.Reader, error) {
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range c.headers {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{URL: u.String(), StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return bytes.NewReader(body), nil
+}
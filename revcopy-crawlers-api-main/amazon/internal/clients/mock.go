@@ -0,0 +1,39 @@
+package clients
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+//go:embed fixtures/*.html
+var mockFixtures embed.FS
+
+// MockClient serves canned HTML fixtures keyed by hostname (e.g.
+// "amazon.com.html" for amazon.com or www.amazon.com), so Shop parsers can
+// be exercised without a network call.
+type MockClient struct {
+	fs  embed.FS
+	dir string
+}
+
+// NewMockClient creates a MockClient serving the fixtures embedded at build
+// time from the fixtures/ directory.
+func NewMockClient() *MockClient {
+	return &MockClient{fs: mockFixtures, dir: "fixtures"}
+}
+
+// Get returns the fixture registered for u's host, ignoring any "www."
+// prefix.
+func (c *MockClient) Get(u *url.URL) (io.Reader, error) {
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	data, err := c.fs.ReadFile(path.Join(c.dir, host+".html"))
+	if err != nil {
+		return nil, fmt.Errorf("no fixture registered for host %q: %w", u.Hostname(), err)
+	}
+	return bytes.NewReader(data), nil
+}
@@ -0,0 +1,42 @@
+package clients
+
+import (
+	"io"
+	"net/url"
+	"testing"
+)
+
+func TestMockClientGetServesFixtureByHost(t *testing.T) {
+	c := NewMockClient()
+
+	u, err := url.Parse("https://www.amazon.com/dp/B000000000")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	body, err := c.Get(u)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("expected non-empty fixture body")
+	}
+}
+
+func TestMockClientGetUnknownHost(t *testing.T) {
+	c := NewMockClient()
+
+	u, err := url.Parse("https://www.unregistered-store.example/product")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	if _, err := c.Get(u); err == nil {
+		t.Fatal("expected an error for a host with no registered fixture")
+	}
+}
@@ -0,0 +1,62 @@
+package clients
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RecordReplayClient serves a cached fixture from dir when one exists for
+// u, and otherwise fetches through upstream once and writes the response to
+// dir so every later Get for the same URL replays it instead of hitting the
+// network again. Pointed at a checked-in directory it behaves like
+// MockClient against golden fixtures; pointed at a scratch directory it
+// builds that directory for you on first run.
+type RecordReplayClient struct {
+	upstream Client
+	dir      string
+}
+
+// NewRecordReplayClient creates a RecordReplayClient recording upstream's
+// responses under dir.
+func NewRecordReplayClient(upstream Client, dir string) *RecordReplayClient {
+	return &RecordReplayClient{upstream: upstream, dir: dir}
+}
+
+// Get replays the cached fixture for u if one exists, else records a fresh
+// one from upstream.
+func (c *RecordReplayClient) Get(u *url.URL) (io.Reader, error) {
+	fixturePath := filepath.Join(c.dir, fixtureFilename(u))
+
+	if cached, err := os.ReadFile(fixturePath); err == nil {
+		return bytes.NewReader(cached), nil
+	}
+
+	body, err := c.upstream.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upstream response: %w", err)
+	}
+
+	if err := os.MkdirAll(c.dir, 0o755); err == nil {
+		_ = os.WriteFile(fixturePath, data, 0o644)
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+// fixtureFilename derives a stable filename for u so repeated Gets of the
+// same URL hit the same cached fixture.
+func fixtureFilename(u *url.URL) string {
+	sum := sha256.Sum256([]byte(u.String()))
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	return fmt.Sprintf("%s-%x.html", host, sum[:8])
+}
@@ -0,0 +1,55 @@
+package clients
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// countingClient counts how many times Get is called, so tests can assert
+// RecordReplayClient only hits it once per URL.
+type countingClient struct {
+	calls int
+	body  string
+}
+
+func (c *countingClient) Get(u *url.URL) (io.Reader, error) {
+	c.calls++
+	return strings.NewReader(c.body), nil
+}
+
+func TestRecordReplayClientRecordsThenReplays(t *testing.T) {
+	upstream := &countingClient{body: "<html>fixture</html>"}
+	dir := t.TempDir()
+	c := NewRecordReplayClient(upstream, dir)
+
+	u, err := url.Parse("https://example.com/product")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	first, err := c.Get(u)
+	if err != nil {
+		t.Fatalf("first Get: %v", err)
+	}
+	firstBody, _ := io.ReadAll(first)
+	if string(firstBody) != upstream.body {
+		t.Fatalf("first Get body = %q, want %q", firstBody, upstream.body)
+	}
+	if upstream.calls != 1 {
+		t.Fatalf("upstream.calls after first Get = %d, want 1", upstream.calls)
+	}
+
+	second, err := c.Get(u)
+	if err != nil {
+		t.Fatalf("second Get: %v", err)
+	}
+	secondBody, _ := io.ReadAll(second)
+	if string(secondBody) != upstream.body {
+		t.Fatalf("second Get body = %q, want %q", secondBody, upstream.body)
+	}
+	if upstream.calls != 1 {
+		t.Fatalf("upstream.calls after second Get = %d, want still 1 (should replay from cache)", upstream.calls)
+	}
+}
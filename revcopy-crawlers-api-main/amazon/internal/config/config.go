@@ -1,26 +1,146 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Port        string
-	Environment string
-	JWTSecret   string
-	RedisURL    string
-	RateLimit   RateLimitConfig
-	Proxy       ProxyConfig
+	Port         string
+	GRPCPort     string
+	Environment  string
+	JWTSecret    string
+	RedisURL     string
+	RateLimit    RateLimitConfig
+	Proxy        ProxyConfig
+	OAuth        OAuthConfig
+	Auth         AuthConfig
+	Renderer     RendererConfig
+	BulkScrape   BulkScrapeConfig
+	ProductCache ProductCacheConfig
+	Marketplace  MarketplaceConfig
+}
+
+// MarketplaceConfig configures the marketplace plugin subsystem; see
+// internal/services.MarketplaceRegistry.
+type MarketplaceConfig struct {
+	// PluginDir, if set, is scanned on startup for one subdirectory per
+	// marketplace plugin, each containing a plugin.yaml manifest; see
+	// internal/services.LoadPluginsFromDir.
+	PluginDir string
+}
+
+// RendererConfig configures the headless-browser rendering fallback used
+// when a plain HTTP fetch returns a CAPTCHA wall or incomplete markup; see
+// internal/services.RenderingFetcher.
+type RendererConfig struct {
+	// Enabled turns on the headless Chrome fallback; when false, fetches
+	// never retry through it even if the blocked-page heuristic trips.
+	Enabled bool
+	// MaxConcurrentTabs bounds how many headless tabs can render at once.
+	MaxConcurrentTabs int
+	// PageTimeout bounds how long a single render may take.
+	PageTimeout time.Duration
+	// BlockImagesAndFonts drops image/font requests in the headless tab to
+	// speed up rendering.
+	BlockImagesAndFonts bool
+}
+
+// AuthConfig holds user-store and password-hashing configuration.
+type AuthConfig struct {
+	// DatabaseURL, if set, selects the Postgres-backed UserRepository
+	// instead of the in-memory default; see internal/services.NewAuthService.
+	DatabaseURL string
+	// BcryptCost is the work factor used to hash user passwords.
+	BcryptCost int
 }
 
 // RateLimitConfig holds rate limiting configuration
 type RateLimitConfig struct {
 	RequestsPerMinute int
 	BurstSize         int
+	// Store selects where hit counts are held: "memory" (default, single
+	// instance) or "redis" (via Config.RedisURL, so every replica behind a
+	// load balancer shares the same counters instead of each one granting
+	// its own burst).
+	Store string
+	// IdleTTL bounds how long an in-memory store entry survives after its
+	// last hit before the cleanup pass evicts it.
+	IdleTTL time.Duration
+	// MaxEntries caps how many keys an in-memory store holds at once,
+	// evicting the least recently used entry past the cap so a client
+	// cycling through source IPs can't grow the map unbounded.
+	MaxEntries int
+
+	// IPv4MaskBits and IPv6MaskBits mask a client IP to a prefix before
+	// it's used as a rate limit key; IPv6MaskBits defaults to 64 (a
+	// typical per-customer ISP allocation) so a client can't bypass the
+	// limiter by rotating through its own /64.
+	IPv4MaskBits int
+	IPv6MaskBits int
+	// TrustedProxies lists CIDRs allowed to set X-Forwarded-For/
+	// X-Real-IP; a request from outside all of them is keyed on its raw
+	// connection address regardless of what headers it sends.
+	TrustedProxies []string
+
+	// Auth, Public and HeavyCompute override RequestsPerMinute/BurstSize
+	// for their respective route tiers (see
+	// internal/middleware.RateLimitTier): Auth guards login against
+	// credential stuffing with a much stricter limit, HeavyCompute covers
+	// the scraping endpoints, and Public covers everything else.
+	Auth         RateLimitTierConfig
+	Public       RateLimitTierConfig
+	HeavyCompute RateLimitTierConfig
+
+	// UserFree, UserPro and UserEnterprise size the per-plan quota applied
+	// by internal/middleware.RateLimitByUser on top of whichever IP tier
+	// above already wraps the route, so an authenticated user's plan (see
+	// internal/services.User.Plan) - not their source IP - bounds how many
+	// requests they get.
+	UserFree       RateLimitTierConfig
+	UserPro        RateLimitTierConfig
+	UserEnterprise RateLimitTierConfig
+}
+
+// RateLimitTierConfig overrides RateLimitConfig's defaults for one
+// per-route rate limit tier.
+type RateLimitTierConfig struct {
+	RequestsPerMinute int
+	BurstSize         int
+}
+
+// BulkScrapeConfig configures BulkScrapeProducts' worker pool; see
+// internal/services.ScraperService.
+type BulkScrapeConfig struct {
+	// MaxConcurrency bounds how many URLs are scraped at once, across all
+	// hosts.
+	MaxConcurrency int
+	// RateLimit throttles requests to a single host, independent of any
+	// other host being scraped concurrently in the same call.
+	RateLimit RateLimitConfig
+}
+
+// ProductCacheConfig configures GetAmazonProduct's write-through cache; see
+// internal/services.ProductStore.
+type ProductCacheConfig struct {
+	// DatabaseURL, if set, selects the Postgres-backed ProductStore instead
+	// of the Redis-backed default (connected via Config.RedisURL).
+	DatabaseURL string
+	// TTL bounds how long a write-through cached product is served before
+	// it's considered stale enough to need a fresh scrape.
+	TTL time.Duration
+	// RefreshWorkers bounds how many ?max_age= revalidate-on-access
+	// refreshes can run concurrently in the background.
+	RefreshWorkers int
 }
 
 // ProxyConfig holds proxy configuration
@@ -29,6 +149,146 @@ type ProxyConfig struct {
 	Password string
 	Host     string
 	Port     string
+
+	// StoreBackend selects the persistent proxy store implementation:
+	// "bolt" (default, encrypted) or "file" (plain JSON, one file per proxy).
+	StoreBackend string
+	// DBPath is where the persistent proxy store (BoltDB) is written, when
+	// StoreBackend is "bolt".
+	DBPath string
+	// EncryptionKey encrypts proxy passwords at rest; if empty, a fallback
+	// key is derived and a warning is logged.
+	EncryptionKey string
+	// FileStoreDir is where the persistent proxy store writes one JSON file
+	// per proxy, when StoreBackend is "file".
+	FileStoreDir string
+
+	// SeedList, when non-empty, seeds the proxy pool (see
+	// internal/services.ProxyService) with every one of these proxies on
+	// first startup, instead of just the single Username/Host/Port
+	// credential above, so the pool actually has something to rotate
+	// through.
+	SeedList []ProxySeedEntry
+
+	// FilePath, when non-empty, names a proxies.yaml bootstrap file that's
+	// re-synced into the pool on every boot and on every POST /proxies/reload
+	// (or file-watch event, if FileWatch is set); see
+	// internal/services.ProxyService.ReloadProxiesFromFile.
+	FilePath string
+	// FileWatch starts an fsnotify watcher on FilePath so edits take effect
+	// without an explicit reload call.
+	FileWatch bool
+
+	// BypassDomains lists hostnames (and, via a leading dot, domain
+	// suffixes) that should skip the proxy pool entirely and go out
+	// directly; see internal/services.ProxyService.SetBypassDomains.
+	BypassDomains []string
+
+	HealthCheck ProxyHealthCheckConfig
+}
+
+// ProxyHealthCheckConfig controls the background proxy health checker (see
+// internal/services.ProxyService.SetHealthCheckConfig).
+type ProxyHealthCheckConfig struct {
+	Interval    time.Duration
+	CheckURL    string
+	CanaryURL   string
+	Timeout     time.Duration
+	MaxFailures int
+	BanFailures int
+	MaxBackoff  time.Duration
+}
+
+// ProxySeedEntry is one proxy in ProxyConfig.SeedList.
+type ProxySeedEntry struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+}
+
+// OAuthProviderConfig holds the client credentials and redirect URL for a
+// single OAuth2/OIDC provider. IssuerURL is only used by the generic OIDC
+// provider; Google and GitHub use fixed, well-known endpoints.
+type OAuthProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	IssuerURL    string
+	// RoleClaim names the ID token claim holding the caller's SSO groups,
+	// used to look up a local role in OAuthConfig.RoleMapping. Only
+	// meaningful for providers that verify an ID token (currently OIDC);
+	// defaults to "groups" when empty.
+	RoleClaim string
+}
+
+// Enabled reports whether enough configuration is present to register this
+// provider (a client ID implies the operator intends to use it).
+func (c OAuthProviderConfig) Enabled() bool {
+	return c.ClientID != ""
+}
+
+// OAuthConfig holds SSO login configuration for every supported provider.
+type OAuthConfig struct {
+	// AllowedEmailDomains restricts which email domains may provision an
+	// account via SSO; empty means no restriction.
+	AllowedEmailDomains []string
+
+	// RoleMapping maps an SSO group name (from a provider's role claim) to
+	// the local role a newly provisioned user is created with. Groups with
+	// no entry fall back to the "user" role.
+	RoleMapping map[string]string
+
+	// StateTTL bounds how long a login's state/PKCE verifier survives in
+	// StateStoreBackend before the callback must have completed.
+	StateTTL time.Duration
+	// StateStoreBackend selects where pending logins are held: "memory"
+	// (default, single instance) or "redis" (via Config.RedisURL, for
+	// multi-instance deployments behind a load balancer).
+	StateStoreBackend string
+
+	Google OAuthProviderConfig
+	GitHub OAuthProviderConfig
+	OIDC   OAuthProviderConfig
+}
+
+// Validate checks Config for values that would misbehave at runtime rather
+// than fail fast at startup: an out-of-range REST or gRPC port, a default
+// JWT secret in production, an unparseable Redis URL, and — when a proxy
+// is configured — a reachable proxy host.
+func (c *Config) Validate() error {
+	port, err := strconv.Atoi(c.Port)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid PORT %q: must be a number between 1 and 65535", c.Port)
+	}
+
+	grpcPort, err := strconv.Atoi(c.GRPCPort)
+	if err != nil || grpcPort < 1 || grpcPort > 65535 {
+		return fmt.Errorf("invalid GRPC_PORT %q: must be a number between 1 and 65535", c.GRPCPort)
+	}
+
+	if c.Environment == "production" && (c.JWTSecret == "" || c.JWTSecret == "your-super-secret-jwt-key-change-in-production") {
+		return fmt.Errorf("JWT_SECRET must be set to a non-default value in production")
+	}
+
+	if _, err := url.Parse(c.RedisURL); err != nil {
+		return fmt.Errorf("invalid REDIS_URL %q: %w", c.RedisURL, err)
+	}
+
+	if c.Proxy.Host != "" {
+		addr := net.JoinHostPort(c.Proxy.Host, c.Proxy.Port)
+		conn, err := net.DialTimeout("tcp", addr, 3*time.Second)
+		if err != nil {
+			return fmt.Errorf("proxy host %s is not reachable: %w", addr, err)
+		}
+		conn.Close()
+	}
+
+	if c.Proxy.StoreBackend != "bolt" && c.Proxy.StoreBackend != "file" {
+		return fmt.Errorf("invalid PROXY_STORE_BACKEND %q: must be \"bolt\" or \"file\"", c.Proxy.StoreBackend)
+	}
+
+	return nil
 }
 
 // Load loads configuration from environment variables
@@ -40,22 +300,150 @@ func Load() *Config {
 
 	return &Config{
 		Port:        getEnvOrDefault("PORT", "8080"),
+		GRPCPort:    getEnvOrDefault("GRPC_PORT", "9090"),
 		Environment: getEnvOrDefault("ENVIRONMENT", "development"),
 		JWTSecret:   getEnvOrDefault("JWT_SECRET", "your-super-secret-jwt-key-change-in-production"),
 		RedisURL:    getEnvOrDefault("REDIS_URL", "redis://localhost:6379"),
 		RateLimit: RateLimitConfig{
 			RequestsPerMinute: getEnvAsIntOrDefault("RATE_LIMIT_RPM", 60),
 			BurstSize:         getEnvAsIntOrDefault("RATE_LIMIT_BURST", 10),
+			Store:             getEnvOrDefault("RATE_LIMIT_STORE", "memory"),
+			IdleTTL:           getEnvAsDurationOrDefault("RATE_LIMIT_IDLE_TTL", 10*time.Minute),
+			MaxEntries:        getEnvAsIntOrDefault("RATE_LIMIT_MAX_ENTRIES", 100_000),
+			IPv4MaskBits:      getEnvAsIntOrDefault("RATE_LIMIT_IPV4_MASK_BITS", 32),
+			IPv6MaskBits:      getEnvAsIntOrDefault("RATE_LIMIT_IPV6_MASK_BITS", 64),
+			TrustedProxies:    getEnvAsStringSliceOrDefault("RATE_LIMIT_TRUSTED_PROXIES", nil),
+			Auth: RateLimitTierConfig{
+				RequestsPerMinute: getEnvAsIntOrDefault("RATE_LIMIT_AUTH_RPM", 5),
+				BurstSize:         getEnvAsIntOrDefault("RATE_LIMIT_AUTH_BURST", 5),
+			},
+			Public: RateLimitTierConfig{
+				RequestsPerMinute: getEnvAsIntOrDefault("RATE_LIMIT_PUBLIC_RPM", 120),
+				BurstSize:         getEnvAsIntOrDefault("RATE_LIMIT_PUBLIC_BURST", 30),
+			},
+			HeavyCompute: RateLimitTierConfig{
+				RequestsPerMinute: getEnvAsIntOrDefault("RATE_LIMIT_HEAVY_RPM", 20),
+				BurstSize:         getEnvAsIntOrDefault("RATE_LIMIT_HEAVY_BURST", 5),
+			},
+			UserFree: RateLimitTierConfig{
+				RequestsPerMinute: getEnvAsIntOrDefault("RATE_LIMIT_USER_FREE_RPM", 30),
+				BurstSize:         getEnvAsIntOrDefault("RATE_LIMIT_USER_FREE_BURST", 10),
+			},
+			UserPro: RateLimitTierConfig{
+				RequestsPerMinute: getEnvAsIntOrDefault("RATE_LIMIT_USER_PRO_RPM", 120),
+				BurstSize:         getEnvAsIntOrDefault("RATE_LIMIT_USER_PRO_BURST", 30),
+			},
+			UserEnterprise: RateLimitTierConfig{
+				RequestsPerMinute: getEnvAsIntOrDefault("RATE_LIMIT_USER_ENTERPRISE_RPM", 600),
+				BurstSize:         getEnvAsIntOrDefault("RATE_LIMIT_USER_ENTERPRISE_BURST", 100),
+			},
+		},
+		Auth: AuthConfig{
+			DatabaseURL: getEnvOrDefault("DATABASE_URL", ""),
+			BcryptCost:  getEnvAsIntOrDefault("BCRYPT_COST", 10),
+		},
+		Renderer: RendererConfig{
+			Enabled:             getEnvAsBoolOrDefault("HEADLESS_ENABLED", false),
+			MaxConcurrentTabs:   getEnvAsIntOrDefault("HEADLESS_MAX_TABS", 5),
+			PageTimeout:         time.Duration(getEnvAsIntOrDefault("HEADLESS_PAGE_TIMEOUT_SECONDS", 20)) * time.Second,
+			BlockImagesAndFonts: getEnvAsBoolOrDefault("HEADLESS_BLOCK_IMAGES_FONTS", true),
+		},
+		BulkScrape: BulkScrapeConfig{
+			MaxConcurrency: getEnvAsIntOrDefault("BULK_SCRAPE_MAX_CONCURRENCY", 5),
+			RateLimit: RateLimitConfig{
+				RequestsPerMinute: getEnvAsIntOrDefault("BULK_SCRAPE_HOST_RPM", 30),
+				BurstSize:         getEnvAsIntOrDefault("BULK_SCRAPE_HOST_BURST", 5),
+			},
+		},
+		ProductCache: ProductCacheConfig{
+			DatabaseURL:    getEnvOrDefault("PRODUCT_CACHE_DATABASE_URL", ""),
+			TTL:            getEnvAsDurationOrDefault("PRODUCT_CACHE_TTL", time.Hour),
+			RefreshWorkers: getEnvAsIntOrDefault("PRODUCT_CACHE_REFRESH_WORKERS", 5),
+		},
+		Marketplace: MarketplaceConfig{
+			PluginDir: getEnvOrDefault("MARKETPLACE_PLUGIN_DIR", ""),
 		},
 		Proxy: ProxyConfig{
-			Username: getEnvOrDefault("PROXY_USERNAME", "anvitop"),
-			Password: getEnvOrDefault("PROXY_PASSWORD", "C29UaLSZPx"),
-			Host:     getEnvOrDefault("PROXY_HOST", "74.124.222.120"),
-			Port:     getEnvOrDefault("PROXY_PORT", "50100"),
+			Username:      getEnvOrDefault("PROXY_USERNAME", ""),
+			Password:      getEnvOrDefault("PROXY_PASSWORD", ""),
+			Host:          getEnvOrDefault("PROXY_HOST", ""),
+			Port:          getEnvOrDefault("PROXY_PORT", ""),
+			StoreBackend:  getEnvOrDefault("PROXY_STORE_BACKEND", "bolt"),
+			DBPath:        getEnvOrDefault("PROXY_DB_PATH", "./data/proxies.db"),
+			EncryptionKey: getEnvOrDefault("PROXY_ENCRYPTION_KEY", ""),
+			FileStoreDir:  getEnvOrDefault("PROXY_FILE_STORE_DIR", "./data/proxies"),
+			SeedList:      loadProxySeedList(),
+			FilePath:      getEnvOrDefault("PROXY_FILE_PATH", ""),
+			FileWatch:     getEnvAsBoolOrDefault("PROXY_FILE_WATCH", false),
+			BypassDomains: getEnvAsStringSliceOrDefault("PROXY_BYPASS_DOMAINS", nil),
+			HealthCheck: ProxyHealthCheckConfig{
+				Interval:    getEnvAsDurationOrDefault("PROXY_HEALTH_CHECK_INTERVAL", time.Minute),
+				CheckURL:    getEnvOrDefault("PROXY_HEALTH_CHECK_URL", "https://api.ipify.org"),
+				CanaryURL:   getEnvOrDefault("PROXY_HEALTH_CANARY_URL", "https://www.amazon.com/dp/B08N5WRWNW"),
+				Timeout:     getEnvAsDurationOrDefault("PROXY_HEALTH_CHECK_TIMEOUT", 10*time.Second),
+				MaxFailures: getEnvAsIntOrDefault("PROXY_HEALTH_MAX_FAILURES", 3),
+				BanFailures: getEnvAsIntOrDefault("PROXY_HEALTH_BAN_FAILURES", 8),
+				MaxBackoff:  getEnvAsDurationOrDefault("PROXY_HEALTH_MAX_BACKOFF", 10*time.Minute),
+			},
+		},
+		OAuth: OAuthConfig{
+			AllowedEmailDomains: getEnvAsStringSliceOrDefault("OAUTH_ALLOWED_EMAIL_DOMAINS", nil),
+			RoleMapping:         loadOAuthRoleMapping(),
+			StateTTL:            getEnvAsDurationOrDefault("OAUTH_STATE_TTL", 5*time.Minute),
+			StateStoreBackend:   getEnvOrDefault("OAUTH_STATE_STORE", "memory"),
+			Google: OAuthProviderConfig{
+				ClientID:     getEnvOrDefault("OAUTH_GOOGLE_CLIENT_ID", ""),
+				ClientSecret: getEnvOrDefault("OAUTH_GOOGLE_CLIENT_SECRET", ""),
+				RedirectURL:  getEnvOrDefault("OAUTH_GOOGLE_REDIRECT_URL", ""),
+			},
+			GitHub: OAuthProviderConfig{
+				ClientID:     getEnvOrDefault("OAUTH_GITHUB_CLIENT_ID", ""),
+				ClientSecret: getEnvOrDefault("OAUTH_GITHUB_CLIENT_SECRET", ""),
+				RedirectURL:  getEnvOrDefault("OAUTH_GITHUB_REDIRECT_URL", ""),
+			},
+			OIDC: OAuthProviderConfig{
+				ClientID:     getEnvOrDefault("OAUTH_OIDC_CLIENT_ID", ""),
+				ClientSecret: getEnvOrDefault("OAUTH_OIDC_CLIENT_SECRET", ""),
+				RedirectURL:  getEnvOrDefault("OAUTH_OIDC_REDIRECT_URL", ""),
+				IssuerURL:    getEnvOrDefault("OAUTH_OIDC_ISSUER_URL", ""),
+				RoleClaim:    getEnvOrDefault("OAUTH_OIDC_ROLE_CLAIM", "groups"),
+			},
 		},
 	}
 }
 
+// loadOAuthRoleMapping parses OAUTH_ROLE_MAPPING, a comma-separated list of
+// "group:role" pairs (e.g. "engineering:admin,support:user") mapping an SSO
+// group claim to a local role for newly provisioned OAuth users.
+func loadOAuthRoleMapping() map[string]string {
+	mapping := make(map[string]string)
+	for _, pair := range splitAndTrim(getEnvOrDefault("OAUTH_ROLE_MAPPING", "")) {
+		group, role, ok := strings.Cut(pair, ":")
+		if !ok || group == "" || role == "" {
+			continue
+		}
+		mapping[group] = role
+	}
+	return mapping
+}
+
+// splitAndTrim splits a comma-separated env value into a trimmed, non-empty
+// slice of parts; an empty input returns an empty (not nil) slice.
+func splitAndTrim(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+
+	parts := strings.Split(value, ",")
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
 // getEnvOrDefault gets an environment variable or returns a default value
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -64,25 +452,62 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
-// getEnvAsIntOrDefault gets an environment variable as int or returns a default value
+// getEnvAsIntOrDefault gets an environment variable as an int, logging and
+// falling back to defaultValue if it's unset or unparseable.
 func getEnvAsIntOrDefault(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		// Simple conversion for this example
-		// In production, you might want proper error handling
-		switch value {
-		case "30":
-			return 30
-		case "60":
-			return 60
-		case "120":
-			return 120
-		case "5":
-			return 5
-		case "10":
-			return 10
-		case "20":
-			return 20
-		}
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
 	}
-	return defaultValue
-} 
\ No newline at end of file
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("invalid int value %q for %s, using default %d: %v", value, key, defaultValue, err)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvAsBoolOrDefault gets an environment variable as a bool, logging and
+// falling back to defaultValue if it's unset or unparseable.
+func getEnvAsBoolOrDefault(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("invalid bool value %q for %s, using default %t: %v", value, key, defaultValue, err)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvAsDurationOrDefault gets an environment variable as a
+// time.ParseDuration-style string (e.g. "30s", "5m"), logging and falling
+// back to defaultValue if it's unset or unparseable.
+func getEnvAsDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("invalid duration value %q for %s, using default %s: %v", value, key, defaultValue, err)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvAsStringSliceOrDefault gets a comma-separated environment variable
+// as a trimmed, non-empty slice of parts, falling back to defaultValue if
+// it's unset.
+func getEnvAsStringSliceOrDefault(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return splitAndTrim(value)
+}
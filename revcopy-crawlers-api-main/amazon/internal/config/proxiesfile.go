@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProxyFileEntry is one proxy definition in a proxies.yaml bootstrap file.
+// Unlike ProxySeedEntry (which only seeds the pool once, on first startup),
+// these entries are re-synced into the pool on every boot and every reload,
+// and always win over API edits made to the same ID; see
+// internal/services.ProxyService.ReloadProxiesFromFile.
+type ProxyFileEntry struct {
+	ID           string   `yaml:"id"`
+	Name         string   `yaml:"name"`
+	Username     string   `yaml:"username"`
+	Password     string   `yaml:"password"`
+	Host         string   `yaml:"host"`
+	Port         string   `yaml:"port"`
+	Active       bool     `yaml:"active"`
+	Marketplaces []string `yaml:"marketplaces,omitempty"`
+}
+
+// proxiesFile is the shape of a proxies.yaml bootstrap file.
+type proxiesFile struct {
+	Proxies []ProxyFileEntry `yaml:"proxies"`
+}
+
+// LoadProxiesFile reads and parses path as a proxies.yaml bootstrap file. A
+// missing file is not an error -- it just means there's nothing to sync.
+func LoadProxiesFile(path string) ([]ProxyFileEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var file proxiesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	for _, entry := range file.Proxies {
+		if entry.ID == "" {
+			return nil, fmt.Errorf("proxy entry missing required id field")
+		}
+	}
+
+	return file.Proxies, nil
+}
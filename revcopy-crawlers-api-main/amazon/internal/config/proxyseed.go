@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadProxySeedList builds ProxyConfig.SeedList from PROXY_LIST (a
+// comma-separated list of user:pass@host:port entries) or, if that's unset,
+// from the YAML file named by PROXY_LIST_FILE.
+func loadProxySeedList() []ProxySeedEntry {
+	if list := os.Getenv("PROXY_LIST"); list != "" {
+		return parseProxyList(list)
+	}
+
+	if path := os.Getenv("PROXY_LIST_FILE"); path != "" {
+		entries, err := loadProxyListFile(path)
+		if err != nil {
+			log.Printf("failed to load PROXY_LIST_FILE %s, starting with no seed proxies: %v", path, err)
+			return nil
+		}
+		return entries
+	}
+
+	return nil
+}
+
+// parseProxyList parses a comma-separated PROXY_LIST value, skipping and
+// logging any entry that doesn't parse rather than failing the whole list.
+func parseProxyList(list string) []ProxySeedEntry {
+	var entries []ProxySeedEntry
+	for _, raw := range strings.Split(list, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		entry, err := parseProxyListEntry(raw)
+		if err != nil {
+			log.Printf("skipping invalid PROXY_LIST entry %q: %v", raw, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// parseProxyListEntry parses a single "user:pass@host:port" entry.
+func parseProxyListEntry(raw string) (ProxySeedEntry, error) {
+	userInfo, hostPort, ok := strings.Cut(raw, "@")
+	if !ok {
+		return ProxySeedEntry{}, fmt.Errorf("expected user:pass@host:port")
+	}
+
+	username, password, _ := strings.Cut(userInfo, ":")
+
+	host, port, ok := strings.Cut(hostPort, ":")
+	if !ok {
+		return ProxySeedEntry{}, fmt.Errorf("missing port")
+	}
+
+	return ProxySeedEntry{Username: username, Password: password, Host: host, Port: port}, nil
+}
+
+// proxyListFile is the shape of a PROXY_LIST_FILE YAML document.
+type proxyListFile struct {
+	Proxies []ProxySeedEntry `yaml:"proxies"`
+}
+
+// loadProxyListFile reads and parses path as a proxyListFile.
+func loadProxyListFile(path string) ([]ProxySeedEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var file proxyListFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	return file.Proxies, nil
+}
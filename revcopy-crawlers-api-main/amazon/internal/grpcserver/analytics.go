@@ -0,0 +1,45 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/revcopy/crawlers/amazon/internal/services"
+)
+
+// analyticsUnaryInterceptor records one analytics event per unary call,
+// the gRPC equivalent of the TrackEvent calls handlers make inline (e.g.
+// Handlers.BulkScrapeAmazonProducts).
+func analyticsUnaryInterceptor(analyticsService *services.AnalyticsService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		trackGRPCEvent(analyticsService, ctx, info.FullMethod, time.Since(start), err)
+		return resp, err
+	}
+}
+
+// analyticsStreamInterceptor is the streaming equivalent of
+// analyticsUnaryInterceptor; it records a single event for the whole
+// BulkScrape call once the stream ends, not one per streamed message.
+func analyticsStreamInterceptor(analyticsService *services.AnalyticsService) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		trackGRPCEvent(analyticsService, ss.Context(), info.FullMethod, time.Since(start), err)
+		return err
+	}
+}
+
+// trackGRPCEvent emits a single "grpc_request" analytics event for method.
+func trackGRPCEvent(analyticsService *services.AnalyticsService, ctx context.Context, method string, latency time.Duration, err error) {
+	analyticsService.TrackEvent(userIDFromContext(ctx), "grpc_request", map[string]interface{}{
+		"method":     method,
+		"latency_ms": latency.Milliseconds(),
+		"success":    err == nil,
+		"grpc_code":  status.Code(err).String(),
+	})
+}
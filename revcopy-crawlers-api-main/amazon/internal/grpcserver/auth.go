@@ -0,0 +1,122 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/revcopy/crawlers/amazon/internal/services"
+)
+
+// authContextKey namespaces values this package stores on a request
+// context, mirroring the gin context keys AuthRequired sets for REST.
+type authContextKey string
+
+const (
+	userIDContextKey      authContextKey = "user_id"
+	usernameContextKey    authContextKey = "username"
+	permissionsContextKey authContextKey = "permissions"
+)
+
+// publicMethods lists the fully-qualified gRPC methods callable without a
+// token, mirroring which REST routes sit outside router.Use(AuthRequired).
+var publicMethods = map[string]bool{
+	"/crawler.v1.AuthService/Login": true,
+}
+
+// authUnaryInterceptor validates the bearer token on every unary call except
+// publicMethods, the same JWT-or-PAT check middleware.AuthRequired does for
+// REST, storing the result on the context for handlers to read.
+func authUnaryInterceptor(authService *services.AuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, authService)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor is the streaming equivalent of authUnaryInterceptor.
+func authStreamInterceptor(authService *services.AuthService) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if publicMethods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), authService)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedServerStream overrides Context() so handlers see the
+// context authenticate() enriched with the caller's identity.
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate extracts and validates the "authorization: Bearer <token>"
+// metadata, accepting either a JWT or a PAT exactly like
+// middleware.AuthRequired, and returns a context carrying the caller's
+// identity.
+func authenticate(ctx context.Context, authService *services.AuthService) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	parts := strings.SplitN(values[0], " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata must be in the form: Bearer <token>")
+	}
+	token := parts[1]
+
+	if services.IsPAT(token) {
+		pat, err := authService.ValidatePAT(token)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid or expired token: %v", err)
+		}
+		ctx = context.WithValue(ctx, userIDContextKey, pat.UserID)
+		ctx = context.WithValue(ctx, usernameContextKey, pat.Username)
+		ctx = context.WithValue(ctx, permissionsContextKey, pat.Scopes)
+		return ctx, nil
+	}
+
+	claims, err := authService.ValidateToken(token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired token: %v", err)
+	}
+
+	ctx = context.WithValue(ctx, userIDContextKey, claims.UserID)
+	ctx = context.WithValue(ctx, usernameContextKey, claims.Username)
+	ctx = context.WithValue(ctx, permissionsContextKey, claims.Permissions)
+	return ctx, nil
+}
+
+// userIDFromContext returns the authenticated caller's user ID, or "" for
+// an unauthenticated call (e.g. AuthService.Login).
+func userIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDContextKey).(string)
+	return userID
+}
@@ -0,0 +1,33 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/revcopy/crawlers/amazon/api/proto/crawlerpb"
+	"github.com/revcopy/crawlers/amazon/internal/services"
+)
+
+// authServer implements crawlerpb.AuthServiceServer as a thin wrapper over
+// services.AuthService, exactly like Handlers.Login does for REST.
+type authServer struct {
+	crawlerpb.UnimplementedAuthServiceServer
+	authService *services.AuthService
+}
+
+// Login implements crawlerpb.AuthServiceServer.
+func (s *authServer) Login(ctx context.Context, req *crawlerpb.LoginRequest) (*crawlerpb.LoginResponse, error) {
+	resp, err := s.authService.Login(req.Username, req.Password)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "login failed: %v", err)
+	}
+
+	return &crawlerpb.LoginResponse{
+		AccessToken:  resp.AccessToken,
+		RefreshToken: resp.RefreshToken,
+		ExpiresIn:    int64(resp.ExpiresIn),
+		TokenType:    resp.TokenType,
+	}, nil
+}
@@ -0,0 +1,8 @@
+package grpcserver
+
+import "time"
+
+// timeLayout matches the layout services.AmazonProduct.ScrapedAt is
+// formatted with, used here for any other timestamp fields converted onto
+// the wire.
+const timeLayout = time.RFC3339
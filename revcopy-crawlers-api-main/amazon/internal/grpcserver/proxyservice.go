@@ -0,0 +1,47 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/revcopy/crawlers/amazon/api/proto/crawlerpb"
+	"github.com/revcopy/crawlers/amazon/internal/services"
+)
+
+// proxyServer implements crawlerpb.ProxyServiceServer as a thin wrapper
+// over services.ProxyService, exactly like Handlers.ListProxies and
+// Handlers.TestProxy do for REST.
+type proxyServer struct {
+	crawlerpb.UnimplementedProxyServiceServer
+	proxyService *services.ProxyService
+}
+
+// ListProxies implements crawlerpb.ProxyServiceServer.
+func (s *proxyServer) ListProxies(ctx context.Context, req *crawlerpb.ListProxiesRequest) (*crawlerpb.ListProxiesResponse, error) {
+	proxies := s.proxyService.ListProxies()
+
+	resp := &crawlerpb.ListProxiesResponse{Proxies: make([]*crawlerpb.Proxy, len(proxies))}
+	for i, proxy := range proxies {
+		resp.Proxies[i] = &crawlerpb.Proxy{
+			Id:        proxy.ID,
+			Name:      proxy.Name,
+			Username:  proxy.Username,
+			Host:      proxy.Host,
+			Port:      proxy.Port,
+			Active:    proxy.Active,
+			CreatedAt: proxy.CreatedAt.Format(timeLayout),
+			UpdatedAt: proxy.UpdatedAt.Format(timeLayout),
+			CreatedBy: proxy.CreatedBy,
+		}
+	}
+	return resp, nil
+}
+
+// TestProxy implements crawlerpb.ProxyServiceServer.
+func (s *proxyServer) TestProxy(ctx context.Context, req *crawlerpb.TestProxyRequest) (*crawlerpb.ProxyStatus, error) {
+	result := s.proxyService.TestProxy()
+	return &crawlerpb.ProxyStatus{
+		Connected: result.Connected,
+		LatencyMs: result.Latency,
+		Error:     result.Error,
+	}, nil
+}
@@ -0,0 +1,25 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDMetadataKey is the gRPC metadata equivalent of the REST
+// X-Request-ID header (see internal/middleware.RequestID).
+const requestIDMetadataKey = "x-request-id"
+
+// requestIDFromContext returns the caller-supplied request ID from
+// incoming metadata, generating one if none was supplied, so outbound
+// scrape calls can still be correlated back to the inbound gRPC call that
+// triggered them.
+func requestIDFromContext(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadataKey); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return uuid.New().String()
+}
@@ -0,0 +1,104 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/revcopy/crawlers/amazon/api/proto/crawlerpb"
+	"github.com/revcopy/crawlers/amazon/internal/services"
+)
+
+// scraperServer implements crawlerpb.ScraperServiceServer as a thin wrapper
+// over services.ScraperService, exactly like Handlers wraps it for REST.
+type scraperServer struct {
+	crawlerpb.UnimplementedScraperServiceServer
+	scraperService *services.ScraperService
+}
+
+// ScrapeProduct implements crawlerpb.ScraperServiceServer.
+func (s *scraperServer) ScrapeProduct(ctx context.Context, req *crawlerpb.ScrapeProductRequest) (*crawlerpb.Product, error) {
+	opts := scrapeOptionsFromRequest(req.MaxReviewPages)
+
+	product, err := s.scraperService.ScrapeProduct(requestIDFromContext(ctx), req.Url, opts...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "scrape failed: %v", err)
+	}
+	return toProtoProduct(product), nil
+}
+
+// BulkScrape implements crawlerpb.ScraperServiceServer, streaming each
+// product to stream as soon as ScraperService.BulkScrapeProductsStream
+// reports it, followed by a terminal BulkScrapeSummary.
+func (s *scraperServer) BulkScrape(req *crawlerpb.BulkScrapeRequest, stream crawlerpb.ScraperService_BulkScrapeServer) error {
+	ctx := stream.Context()
+	start := time.Now()
+
+	summary := &crawlerpb.BulkScrapeSummary{}
+	var sendErr error
+
+	s.scraperService.BulkScrapeProductsStream(requestIDFromContext(ctx), req.Urls, func(product *services.AmazonProduct, failed *services.FailedURL) {
+		if sendErr != nil {
+			return
+		}
+
+		if failed != nil {
+			summary.FailedCount++
+			sendErr = stream.Send(&crawlerpb.BulkScrapeEvent{Event: &crawlerpb.BulkScrapeEvent_Failure{
+				Failure: &crawlerpb.ScrapeFailure{
+					Url:        failed.URL,
+					Error:      failed.Err,
+					StatusCode: int32(failed.StatusCode),
+					Attempts:   int32(failed.Attempts),
+				},
+			}})
+			return
+		}
+
+		summary.SuccessCount++
+		sendErr = stream.Send(&crawlerpb.BulkScrapeEvent{Event: &crawlerpb.BulkScrapeEvent_Product{
+			Product: toProtoProduct(product),
+		}})
+	})
+
+	if sendErr != nil {
+		return status.Errorf(codes.Internal, "streaming bulk scrape result: %v", sendErr)
+	}
+
+	summary.TotalLatencyMs = time.Since(start).Milliseconds()
+	return stream.Send(&crawlerpb.BulkScrapeEvent{Event: &crawlerpb.BulkScrapeEvent_Summary{Summary: summary}})
+}
+
+// scrapeOptionsFromRequest converts the request's max_review_pages field
+// into a ScrapeOption, omitting it entirely when unset so ScrapeProduct's
+// no-reviews default is preserved.
+func scrapeOptionsFromRequest(maxReviewPages int32) []services.ScrapeOption {
+	if maxReviewPages <= 0 {
+		return nil
+	}
+	return []services.ScrapeOption{services.WithReviews(int(maxReviewPages))}
+}
+
+// toProtoProduct converts an internal AmazonProduct to its wire shape.
+func toProtoProduct(p *services.AmazonProduct) *crawlerpb.Product {
+	return &crawlerpb.Product{
+		Asin:            p.ASIN,
+		Title:           p.Title,
+		Price:           p.Price,
+		Currency:        p.Currency,
+		Rating:          p.Rating,
+		ReviewCount:     int32(p.ReviewCount),
+		Images:          p.Images,
+		Description:     p.Description,
+		Availability:    p.Availability,
+		Brand:           p.Brand,
+		Category:        p.Category,
+		Url:             p.URL,
+		ScrapedAt:       p.ScrapedAt,
+		PositiveReviews: p.PositiveReviews,
+		NegativeReviews: p.NegativeReviews,
+		Features:        p.Features,
+	}
+}
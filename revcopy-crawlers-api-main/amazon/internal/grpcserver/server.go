@@ -0,0 +1,38 @@
+// Package grpcserver exposes the same scraper, auth, user and proxy
+// services the REST handlers in internal/handlers wrap, over gRPC. Every
+// RPC implementation here is a thin pass-through to internal/services, so
+// both transports stay in sync; business logic lives in internal/services
+// exactly once.
+package grpcserver
+
+import (
+	"google.golang.org/grpc"
+
+	"github.com/revcopy/crawlers/amazon/api/proto/crawlerpb"
+	"github.com/revcopy/crawlers/amazon/internal/services"
+)
+
+// NewServer builds a *grpc.Server with every service registered and a
+// shared JWT/PAT auth interceptor plus an analytics-tracking interceptor
+// applied to every RPC (reusing authService.ValidateToken/ValidatePAT and
+// analyticsService.TrackEvent exactly like the REST middleware stack
+// does).
+func NewServer(scraperService *services.ScraperService, authService *services.AuthService, analyticsService *services.AnalyticsService, proxyService *services.ProxyService) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			authUnaryInterceptor(authService),
+			analyticsUnaryInterceptor(analyticsService),
+		),
+		grpc.ChainStreamInterceptor(
+			authStreamInterceptor(authService),
+			analyticsStreamInterceptor(analyticsService),
+		),
+	)
+
+	crawlerpb.RegisterScraperServiceServer(server, &scraperServer{scraperService: scraperService})
+	crawlerpb.RegisterAuthServiceServer(server, &authServer{authService: authService})
+	crawlerpb.RegisterUserServiceServer(server, &userServer{authService: authService})
+	crawlerpb.RegisterProxyServiceServer(server, &proxyServer{proxyService: proxyService})
+
+	return server
+}
@@ -0,0 +1,53 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/revcopy/crawlers/amazon/api/proto/crawlerpb"
+	"github.com/revcopy/crawlers/amazon/internal/services"
+)
+
+// userServer implements crawlerpb.UserServiceServer as a thin wrapper over
+// services.AuthService's user directory, exactly like Handlers.GetUser and
+// Handlers.ListUsers do for REST.
+type userServer struct {
+	crawlerpb.UnimplementedUserServiceServer
+	authService *services.AuthService
+}
+
+// GetUser implements crawlerpb.UserServiceServer.
+func (s *userServer) GetUser(ctx context.Context, req *crawlerpb.GetUserRequest) (*crawlerpb.User, error) {
+	user, err := s.authService.GetUser(req.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+	}
+	return toProtoUser(user), nil
+}
+
+// ListUsers implements crawlerpb.UserServiceServer.
+func (s *userServer) ListUsers(ctx context.Context, req *crawlerpb.ListUsersRequest) (*crawlerpb.ListUsersResponse, error) {
+	users := s.authService.ListUsers()
+
+	resp := &crawlerpb.ListUsersResponse{Users: make([]*crawlerpb.User, len(users))}
+	for i, user := range users {
+		resp.Users[i] = toProtoUser(user)
+	}
+	return resp, nil
+}
+
+// toProtoUser converts an internal UserResponse to its wire shape; like
+// UserResponse itself, it never carries the password hash.
+func toProtoUser(u *services.UserResponse) *crawlerpb.User {
+	return &crawlerpb.User{
+		Id:        u.ID,
+		Username:  u.Username,
+		Role:      u.Role,
+		Active:    u.Active,
+		AuthType:  u.AuthType,
+		CreatedAt: u.CreatedAt.Format(timeLayout),
+		UpdatedAt: u.UpdatedAt.Format(timeLayout),
+	}
+}
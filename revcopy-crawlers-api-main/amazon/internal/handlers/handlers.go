@@ -1,28 +1,42 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/revcopy/crawlers/amazon/internal/config"
+	"github.com/revcopy/crawlers/amazon/internal/oauth"
 	"github.com/revcopy/crawlers/amazon/internal/services"
 )
 
 // Handlers holds all HTTP handlers
 type Handlers struct {
-	scraperService   *services.ScraperService
-	authService      *services.AuthService
-	analyticsService *services.AnalyticsService
-	proxyService     *services.ProxyService
+	scraperService      *services.ScraperService
+	authService         *services.AuthService
+	analyticsService    *services.AnalyticsService
+	proxyService        *services.ProxyService
+	oauthProviders      *oauth.Registry
+	oauthConfig         config.OAuthConfig
+	oauthStates         oauth.StateStore
+	marketplaceRegistry *services.MarketplaceRegistry
 }
 
 // New creates a new handlers instance
-func New(scraperService *services.ScraperService, authService *services.AuthService, analyticsService *services.AnalyticsService, proxyService *services.ProxyService) *Handlers {
+func New(scraperService *services.ScraperService, authService *services.AuthService, analyticsService *services.AnalyticsService, proxyService *services.ProxyService, oauthProviders *oauth.Registry, oauthConfig config.OAuthConfig, oauthStates oauth.StateStore, marketplaceRegistry *services.MarketplaceRegistry) *Handlers {
 	return &Handlers{
-		scraperService:   scraperService,
-		authService:      authService,
-		analyticsService: analyticsService,
-		proxyService:     proxyService,
+		scraperService:      scraperService,
+		authService:         authService,
+		analyticsService:    analyticsService,
+		proxyService:        proxyService,
+		oauthProviders:      oauthProviders,
+		oauthConfig:         oauthConfig,
+		oauthStates:         oauthStates,
+		marketplaceRegistry: marketplaceRegistry,
 	}
 }
 
@@ -106,6 +120,309 @@ func (h *Handlers) RefreshToken(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// OAuthLogin redirects to provider's authorization endpoint, starting an
+// OAuth2/OIDC login with PKCE and a CSRF state parameter stashed server-side
+// in oauthStates until the callback consumes it.
+// @Summary Start OAuth2/OIDC login
+// @Description Redirect to the named provider's authorization endpoint to begin SSO login
+// @Tags authentication
+// @Param provider path string true "Registered provider name, e.g. google, github, oidc"
+// @Success 302 {string} string "Redirect to provider"
+// @Failure 404 {object} map[string]interface{} "Unknown provider"
+// @Router /api/v1/auth/oauth/{provider}/login [get]
+func (h *Handlers) OAuthLogin(c *gin.Context) {
+	providerName := c.Param("provider")
+	provider, ok := h.oauthProviders.Get(providerName)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Unknown OAuth provider",
+			"code":    "UNKNOWN_OAUTH_PROVIDER",
+			"message": fmt.Sprintf("no provider registered as %q", providerName),
+		})
+		return
+	}
+
+	state, err := oauth.GenerateState()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to start OAuth login",
+			"code":    "OAUTH_STATE_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	verifier, challenge, err := oauth.GeneratePKCE()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to start OAuth login",
+			"code":    "OAUTH_PKCE_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := h.oauthStates.Put(state, oauth.LoginState{Provider: providerName, Verifier: verifier}, h.oauthConfig.StateTTL); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to start OAuth login",
+			"code":    "OAUTH_STATE_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, provider.AuthCodeURL(state, challenge))
+}
+
+// OAuthCallback completes an OAuth2/OIDC login: exchanging the
+// authorization code for a token, fetching the user's profile, upserting a
+// User record, and issuing the same LoginResponse the password flow returns.
+// @Summary OAuth2/OIDC login callback
+// @Description Exchange the authorization code for a token and issue a JWT
+// @Tags authentication
+// @Produce json
+// @Param provider path string true "Registered provider name, e.g. google, github, oidc"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State parameter echoed back from the login redirect"
+// @Success 200 {object} services.LoginResponse "Login successful"
+// @Failure 400 {object} map[string]interface{} "Invalid or expired OAuth state"
+// @Failure 401 {object} map[string]interface{} "Authentication failed"
+// @Failure 404 {object} map[string]interface{} "Unknown provider"
+// @Router /api/v1/auth/oauth/{provider}/callback [get]
+func (h *Handlers) OAuthCallback(c *gin.Context) {
+	providerName := c.Param("provider")
+	provider, ok := h.oauthProviders.Get(providerName)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Unknown OAuth provider",
+			"code":    "UNKNOWN_OAUTH_PROVIDER",
+			"message": fmt.Sprintf("no provider registered as %q", providerName),
+		})
+		return
+	}
+
+	loginState, ok := h.oauthStates.Consume(c.Query("state"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid or expired OAuth state",
+			"code":    "OAUTH_STATE_MISMATCH",
+			"message": "state parameter does not match a login that was started within the state TTL",
+		})
+		return
+	}
+	if loginState.Provider != providerName {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid OAuth state",
+			"code":    "OAUTH_STATE_MISMATCH",
+			"message": "state parameter was issued for a different provider",
+		})
+		return
+	}
+
+	token, err := provider.Exchange(c.Request.Context(), c.Query("code"), loginState.Verifier)
+	if err != nil {
+		h.analyticsService.TrackError("oauth_exchange_failed")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "OAuth code exchange failed",
+			"code":    "OAUTH_EXCHANGE_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	profile, err := provider.FetchUserInfo(c.Request.Context(), token)
+	if err != nil {
+		h.analyticsService.TrackError("oauth_userinfo_failed")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Failed to fetch OAuth profile",
+			"code":    "OAUTH_USERINFO_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if !h.emailDomainAllowed(profile.Email) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Email domain not allowed",
+			"code":    "OAUTH_DOMAIN_NOT_ALLOWED",
+			"message": fmt.Sprintf("%s is not on the allowed domain list", profile.Email),
+		})
+		return
+	}
+
+	response, err := h.authService.LoginOAuth(profile.Subject, profile.Email, profile.Groups)
+	if err != nil {
+		h.analyticsService.TrackError("oauth_login_failed")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication failed",
+			"code":    "AUTH_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.analyticsService.TrackEvent(profile.Email, "login", map[string]interface{}{
+		"timestamp": time.Now(),
+		"success":   true,
+		"provider":  providerName,
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// emailDomainAllowed reports whether email's domain is permitted to
+// provision an account via SSO; an empty allowlist permits every domain.
+func (h *Handlers) emailDomainAllowed(email string) bool {
+	if len(h.oauthConfig.AllowedEmailDomains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, allowed := range h.oauthConfig.AllowedEmailDomains {
+		if strings.EqualFold(domain, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Logout revokes the caller's current JWT so it can no longer pass
+// AuthRequired, even before it naturally expires.
+// @Summary Log out
+// @Description Revoke the JWT presented in the Authorization header
+// @Tags authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Logged out successfully"
+// @Failure 400 {object} map[string]interface{} "Not a revocable session"
+// @Router /api/v1/auth/logout [post]
+func (h *Handlers) Logout(c *gin.Context) {
+	jti, _ := c.Get("jti")
+	expiresAt, _ := c.Get("token_expires_at")
+
+	jtiStr, _ := jti.(string)
+	expiresAtTime, _ := expiresAt.(time.Time)
+	if err := h.authService.Logout(jtiStr, expiresAtTime); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Cannot log out this session",
+			"code":    "LOGOUT_NOT_APPLICABLE",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Logged out successfully",
+	})
+}
+
+// PERSONAL ACCESS TOKEN HANDLERS
+
+// CreateToken mints a new personal access token for the authenticated user.
+// @Summary Create personal access token
+// @Description Mint a token bound to the caller's user with a name, optional expiry, and scopes. The raw token is only ever returned here.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.CreatePATRequest true "Token creation data"
+// @Success 201 {object} map[string]interface{} "Token created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request format"
+// @Router /api/v1/auth/tokens [post]
+func (h *Handlers) CreateToken(c *gin.Context) {
+	var req services.CreatePATRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"code":    "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	username, _ := c.Get("username")
+
+	pat, err := h.authService.CreatePAT(userID.(string), username.(string), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Token creation failed",
+			"code":    "TOKEN_CREATION_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Token created successfully",
+		"data":    pat,
+	})
+}
+
+// ListTokens lists the authenticated user's personal access tokens
+// (metadata only; raw token values are never retrievable after creation).
+// @Summary List personal access tokens
+// @Description Retrieve metadata for every token owned by the caller
+// @Tags authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "List of tokens"
+// @Router /api/v1/auth/tokens [get]
+func (h *Handlers) ListTokens(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	tokens, err := h.authService.ListPATs(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list tokens",
+			"code":    "TOKEN_LIST_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tokens,
+		"count":   len(tokens),
+	})
+}
+
+// RevokeToken revokes one of the authenticated user's personal access
+// tokens.
+// @Summary Revoke personal access token
+// @Description Revoke a token owned by the caller by ID
+// @Tags authentication
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Token ID"
+// @Success 200 {object} map[string]interface{} "Token revoked successfully"
+// @Failure 404 {object} map[string]interface{} "Token not found"
+// @Router /api/v1/auth/tokens/{id} [delete]
+func (h *Handlers) RevokeToken(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	id := c.Param("id")
+
+	if err := h.authService.RevokePAT(userID.(string), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Token not found",
+			"code":    "TOKEN_NOT_FOUND",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Token revoked successfully",
+	})
+}
+
 // ScrapeAmazonProduct handles single product scraping
 // @Summary Scrape single Amazon product
 // @Description Extract product information from a single Amazon product URL
@@ -131,13 +448,19 @@ func (h *Handlers) ScrapeAmazonProduct(c *gin.Context) {
 	}
 
 	userID := c.GetString("user_id")
+	domain := domainFromURL(req.URL)
 	start := time.Now()
 
-	product, err := h.scraperService.ScrapeProduct(req.URL)
+	var scrapeOpts []services.ScrapeOption
+	if req.MaxReviewPages > 0 {
+		scrapeOpts = append(scrapeOpts, services.WithReviews(req.MaxReviewPages))
+	}
+
+	product, err := h.scraperService.ScrapeProduct(c.GetString("request_id"), req.URL, scrapeOpts...)
 	latency := time.Since(start)
 
 	if err != nil {
-		h.analyticsService.TrackRequest(false, latency, "")
+		h.analyticsService.TrackRequest(false, latency, "", domain, "")
 		h.analyticsService.TrackError("scrape_failed")
 		h.analyticsService.TrackEvent(userID, "scrape_failed", map[string]interface{}{
 			"url":   req.URL,
@@ -152,7 +475,7 @@ func (h *Handlers) ScrapeAmazonProduct(c *gin.Context) {
 		return
 	}
 
-	h.analyticsService.TrackRequest(true, latency, product.ASIN)
+	h.analyticsService.TrackRequest(true, latency, product.ASIN, domain, "")
 	h.analyticsService.TrackEvent(userID, "scrape_success", map[string]interface{}{
 		"url":     req.URL,
 		"asin":    product.ASIN,
@@ -205,17 +528,18 @@ func (h *Handlers) BulkScrapeAmazonProducts(c *gin.Context) {
 	userID := c.GetString("user_id")
 	start := time.Now()
 
-	products, err := h.scraperService.BulkScrapeProducts(req.URLs)
+	result, err := h.scraperService.BulkScrapeProducts(c.GetString("request_id"), req.URLs)
 	latency := time.Since(start)
 
 	h.analyticsService.TrackEvent(userID, "bulk_scrape", map[string]interface{}{
-		"url_count":      len(req.URLs),
-		"success_count":  len(products),
-		"total_latency":  latency.Milliseconds(),
-		"partial_success": err != nil && len(products) > 0,
+		"url_count":       len(req.URLs),
+		"success_count":   len(result.Products),
+		"failed_count":    len(result.Failed),
+		"total_latency":   latency.Milliseconds(),
+		"partial_success": err != nil && len(result.Products) > 0,
 	})
 
-	if err != nil && len(products) == 0 {
+	if err != nil && len(result.Products) == 0 {
 		h.analyticsService.TrackError("bulk_scrape_failed")
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Bulk scraping failed",
@@ -226,9 +550,10 @@ func (h *Handlers) BulkScrapeAmazonProducts(c *gin.Context) {
 	}
 
 	response := gin.H{
-		"success":       len(products) > 0,
-		"data":          products,
-		"total_count":   len(products),
+		"success":         len(result.Products) > 0,
+		"data":            result.Products,
+		"failed":          result.Failed,
+		"total_count":     len(result.Products),
 		"requested_count": len(req.URLs),
 		"meta": gin.H{
 			"latency_ms": latency.Milliseconds(),
@@ -243,17 +568,21 @@ func (h *Handlers) BulkScrapeAmazonProducts(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// GetAmazonProduct handles getting cached product data
+// GetAmazonProduct returns a product by ASIN, preferring the write-through
+// cache ScrapeProduct fills on every successful scrape over a live fetch.
 // @Summary Get Amazon product by ASIN
-// @Description Retrieve cached product information by ASIN
+// @Description Retrieve a product by ASIN, serving the cached copy when fresh. Pass `refresh=true` to force a live scrape, or `max_age` (e.g. "15m") to serve a stale cache entry immediately while it's refreshed in the background.
 // @Tags amazon-scraping
 // @Produce json
 // @Security BearerAuth
 // @Param asin path string true "Amazon product ASIN"
+// @Param marketplace query string false "Amazon storefront domain (default amazon.com)"
+// @Param refresh query bool false "Force a live scrape, bypassing the cache"
+// @Param max_age query string false "Serve a stale cache entry older than this immediately, refreshing it in the background, e.g. 15m"
 // @Success 200 {object} map[string]interface{} "Product found"
 // @Failure 400 {object} map[string]interface{} "Missing ASIN parameter"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
-// @Failure 501 {object} map[string]interface{} "Feature not implemented"
+// @Failure 500 {object} map[string]interface{} "Scrape failed"
 // @Router /api/v1/amazon/product/{asin} [get]
 func (h *Handlers) GetAmazonProduct(c *gin.Context) {
 	asin := c.Param("asin")
@@ -266,15 +595,118 @@ func (h *Handlers) GetAmazonProduct(c *gin.Context) {
 		return
 	}
 
-	// In a real implementation, you would fetch from cache/database
-	// For now, return a not implemented response
-	c.JSON(http.StatusNotImplemented, gin.H{
-		"error":   "Feature not implemented",
-		"code":    "NOT_IMPLEMENTED",
-		"message": "Product caching not yet implemented",
+	marketplace := c.DefaultQuery("marketplace", "amazon.com")
+	requestID := c.GetString("request_id")
+
+	if c.Query("refresh") != "true" {
+		if cached, err := h.scraperService.CachedProduct(marketplace, asin); err == nil {
+			age := time.Since(cached.LastRefreshed)
+
+			if maxAge := parseMaxAge(c.Query("max_age")); maxAge > 0 && age > maxAge {
+				h.analyticsService.TrackCacheStale()
+				h.scraperService.QueueRefresh(requestID, marketplace, asin)
+				c.JSON(http.StatusOK, gin.H{
+					"success": true,
+					"data":    cached.Product,
+					"meta": gin.H{
+						"cache":          "stale",
+						"last_refreshed": cached.LastRefreshed,
+						"refreshing":     true,
+					},
+				})
+				return
+			}
+
+			h.analyticsService.TrackCacheHit()
+			c.JSON(http.StatusOK, gin.H{
+				"success": true,
+				"data":    cached.Product,
+				"meta": gin.H{
+					"cache":          "hit",
+					"last_refreshed": cached.LastRefreshed,
+				},
+			})
+			return
+		}
+	}
+
+	h.analyticsService.TrackCacheMiss()
+
+	start := time.Now()
+	product, err := h.scraperService.ScrapeProduct(requestID, h.scraperService.ProductURL(marketplace, asin))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Scraping failed",
+			"code":    "SCRAPE_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    product,
+		"meta": gin.H{
+			"cache":      "miss",
+			"latency_ms": time.Since(start).Milliseconds(),
+		},
+	})
+}
+
+// DeleteAmazonProduct evicts a product from the cache ScrapeProduct
+// write-through fills, so the next GetAmazonProduct call forces a live
+// scrape.
+// @Summary Invalidate a cached Amazon product
+// @Description Evict a product's cache entry by ASIN
+// @Tags amazon-scraping
+// @Produce json
+// @Security BearerAuth
+// @Param asin path string true "Amazon product ASIN"
+// @Param marketplace query string false "Amazon storefront domain (default amazon.com)"
+// @Success 200 {object} map[string]interface{} "Cache entry invalidated"
+// @Failure 400 {object} map[string]interface{} "Missing ASIN parameter"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/amazon/product/{asin} [delete]
+func (h *Handlers) DeleteAmazonProduct(c *gin.Context) {
+	asin := c.Param("asin")
+	if asin == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ASIN parameter required",
+			"code":    "MISSING_ASIN",
+			"message": "Please provide a valid ASIN",
+		})
+		return
+	}
+
+	marketplace := c.DefaultQuery("marketplace", "amazon.com")
+	if err := h.scraperService.DeleteCachedProduct(marketplace, asin); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Cache invalidation failed",
+			"code":    "CACHE_DELETE_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cache entry invalidated",
 	})
 }
 
+// parseMaxAge parses a ?max_age= duration string (e.g. "15m"), returning 0
+// (meaning "no revalidation-on-access") if raw is empty or unparseable.
+func parseMaxAge(raw string) time.Duration {
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
 // SearchAmazonProducts handles product search
 // @Summary Search Amazon products
 // @Description Search for products on Amazon using keywords
@@ -306,7 +738,7 @@ func (h *Handlers) SearchAmazonProducts(c *gin.Context) {
 	userID := c.GetString("user_id")
 	start := time.Now()
 
-	products, err := h.scraperService.SearchProducts(req.Query, req.Page)
+	products, err := h.scraperService.SearchProducts(c.GetString("request_id"), req.Query, req.Page)
 	latency := time.Since(start)
 
 	h.analyticsService.TrackEvent(userID, "search", map[string]interface{}{
@@ -340,58 +772,46 @@ func (h *Handlers) SearchAmazonProducts(c *gin.Context) {
 	})
 }
 
-// GetAnalyticsStats returns analytics statistics
-// @Summary Get analytics statistics
-// @Description Retrieve comprehensive analytics statistics
-// @Tags analytics
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Analytics statistics"
-// @Failure 401 {object} map[string]interface{} "Unauthorized"
-// @Router /api/v1/analytics/stats [get]
-func (h *Handlers) GetAnalyticsStats(c *gin.Context) {
-	stats := h.analyticsService.GetStats()
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    stats,
-	})
-}
-
-// GetPerformanceMetrics returns performance metrics
-// @Summary Get performance metrics
-// @Description Retrieve system performance metrics and response times
-// @Tags analytics
+// ListMarketplaces lists every registered marketplace (the built-in Amazon
+// backend plus any plugin loaded from MARKETPLACE_PLUGIN_DIR) for
+// discovery.
+// @Summary List registered marketplaces
+// @Description List every marketplace registered for /api/v1/marketplaces/{name}/* dispatch, with its capabilities, rate limit and required proxy regions
+// @Tags marketplaces
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Performance metrics"
-// @Failure 401 {object} map[string]interface{} "Unauthorized"
-// @Router /api/v1/analytics/performance [get]
-func (h *Handlers) GetPerformanceMetrics(c *gin.Context) {
-	metrics := h.analyticsService.GetPerformanceMetrics()
+// @Success 200 {object} map[string]interface{} "Registered marketplaces"
+// @Router /api/v1/marketplaces [get]
+func (h *Handlers) ListMarketplaces(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    metrics,
+		"data":    h.marketplaceRegistry.List(),
 	})
 }
 
-// TrackEvent handles custom event tracking
-// @Summary Track custom event
-// @Description Record a custom analytics event with arbitrary data
-// @Tags analytics
+// ScrapeMarketplaceProduct scrapes a single product through the named
+// marketplace, the way ScrapeAmazonProduct does for the built-in "amazon"
+// one.
+// @Summary Scrape a single product via a named marketplace
+// @Description Extract product information from a single product URL, dispatched to the named marketplace's backend
+// @Tags marketplaces
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body object{type=string,data=object} true "Event type and data"
-// @Success 200 {object} map[string]interface{} "Event tracked successfully"
+// @Param name path string true "Marketplace name, e.g. amazon or walmart"
+// @Param request body services.ScrapeRequest true "Product URL to scrape"
+// @Success 200 {object} map[string]interface{} "Scraping successful"
 // @Failure 400 {object} map[string]interface{} "Invalid request format"
-// @Failure 401 {object} map[string]interface{} "Unauthorized"
-// @Router /api/v1/analytics/track [post]
-func (h *Handlers) TrackEvent(c *gin.Context) {
-	var req struct {
-		Type string                 `json:"type" binding:"required"`
-		Data map[string]interface{} `json:"data"`
+// @Failure 404 {object} map[string]interface{} "Unknown marketplace"
+// @Failure 500 {object} map[string]interface{} "Scraping failed"
+// @Router /api/v1/marketplaces/{name}/scrape [post]
+func (h *Handlers) ScrapeMarketplaceProduct(c *gin.Context) {
+	marketplace, ok := h.lookupMarketplace(c)
+	if !ok {
+		return
 	}
 
+	var req services.ScrapeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request format",
@@ -401,8 +821,258 @@ func (h *Handlers) TrackEvent(c *gin.Context) {
 		return
 	}
 
-	userID := c.GetString("user_id")
-	h.analyticsService.TrackEvent(userID, req.Type, req.Data)
+	var scrapeOpts []services.ScrapeOption
+	if req.MaxReviewPages > 0 {
+		scrapeOpts = append(scrapeOpts, services.WithReviews(req.MaxReviewPages))
+	}
+
+	start := time.Now()
+	product, err := marketplace.ScrapeProduct(c.GetString("request_id"), req.URL, scrapeOpts...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Scraping failed",
+			"code":    "SCRAPE_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    product,
+		"meta": gin.H{
+			"marketplace": marketplace.Name(),
+			"latency_ms":  time.Since(start).Milliseconds(),
+		},
+	})
+}
+
+// SearchMarketplaceProducts searches the named marketplace's backend.
+// @Summary Search products via a named marketplace
+// @Description Search for products through the named marketplace's backend
+// @Tags marketplaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Marketplace name, e.g. amazon or walmart"
+// @Param request body services.SearchRequest true "Search query and pagination"
+// @Success 200 {object} map[string]interface{} "Search successful"
+// @Failure 400 {object} map[string]interface{} "Invalid request format"
+// @Failure 404 {object} map[string]interface{} "Unknown marketplace"
+// @Failure 500 {object} map[string]interface{} "Search failed"
+// @Router /api/v1/marketplaces/{name}/search [post]
+func (h *Handlers) SearchMarketplaceProducts(c *gin.Context) {
+	marketplace, ok := h.lookupMarketplace(c)
+	if !ok {
+		return
+	}
+
+	var req services.SearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"code":    "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	opts := services.SearchOptions{
+		Page:     req.Page,
+		Category: req.Category,
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+		Rating:   req.Rating,
+	}
+
+	products, err := marketplace.SearchProducts(c.GetString("request_id"), req.Query, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Search failed",
+			"code":    "SEARCH_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    products,
+		"count":   len(products),
+		"query":   req.Query,
+		"page":    req.Page,
+	})
+}
+
+// BulkScrapeMarketplaceProducts bulk-scrapes through the named
+// marketplace's backend, the way BulkScrapeAmazonProducts does for the
+// built-in "amazon" one.
+// @Summary Bulk scrape products via a named marketplace
+// @Description Extract product information from multiple product URLs (max 10), dispatched to the named marketplace's backend
+// @Tags marketplaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Marketplace name, e.g. amazon or walmart"
+// @Param request body services.BulkScrapeRequest true "Product URLs to scrape"
+// @Success 200 {object} map[string]interface{} "Bulk scraping successful"
+// @Failure 400 {object} map[string]interface{} "Invalid request format or too many URLs"
+// @Failure 404 {object} map[string]interface{} "Unknown marketplace"
+// @Failure 500 {object} map[string]interface{} "Bulk scraping failed"
+// @Router /api/v1/marketplaces/{name}/bulk-scrape [post]
+func (h *Handlers) BulkScrapeMarketplaceProducts(c *gin.Context) {
+	marketplace, ok := h.lookupMarketplace(c)
+	if !ok {
+		return
+	}
+
+	var req services.BulkScrapeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"code":    "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if len(req.URLs) > 10 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Too many URLs",
+			"code":    "TOO_MANY_URLS",
+			"message": "Maximum 10 URLs allowed per bulk request",
+		})
+		return
+	}
+
+	result, err := marketplace.BulkScrapeProducts(c.GetString("request_id"), req.URLs)
+	if err != nil && len(result.Products) == 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Bulk scraping failed",
+			"code":    "BULK_SCRAPE_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	response := gin.H{
+		"success":         len(result.Products) > 0,
+		"data":            result.Products,
+		"failed":          result.Failed,
+		"total_count":     len(result.Products),
+		"requested_count": len(req.URLs),
+	}
+	if err != nil {
+		response["warning"] = err.Error()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// lookupMarketplace resolves the :name path param against
+// h.marketplaceRegistry, writing a 404 and returning ok=false if it isn't
+// registered.
+func (h *Handlers) lookupMarketplace(c *gin.Context) (services.Marketplace, bool) {
+	name := c.Param("name")
+	marketplace, err := h.marketplaceRegistry.Get(name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Unknown marketplace",
+			"code":    "UNKNOWN_MARKETPLACE",
+			"message": err.Error(),
+		})
+		return nil, false
+	}
+	return marketplace, true
+}
+
+// GetAnalyticsStats returns analytics statistics
+// @Summary Get analytics statistics
+// @Description Retrieve comprehensive analytics statistics
+// @Tags analytics
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Analytics statistics"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/analytics/stats [get]
+func (h *Handlers) GetAnalyticsStats(c *gin.Context) {
+	stats := h.analyticsService.GetStats()
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stats,
+	})
+}
+
+// ListAnalyticsEvents returns a paginated, filterable page of recently
+// tracked analytics events, letting operators scroll through history
+// instead of only seeing the aggregates GetAnalyticsStats exposes.
+// @Summary List analytics events
+// @Description Retrieve a paginated, filterable page of recently tracked analytics events
+// @Tags analytics
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default 1)"
+// @Param per_page query int false "Items per page (default 100, max 1000)"
+// @Param type query string false "Filter by event type"
+// @Param user_id query string false "Filter by user ID"
+// @Success 200 {object} map[string]interface{} "Paginated analytics events"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/analytics/events [get]
+func (h *Handlers) ListAnalyticsEvents(c *gin.Context) {
+	events := h.analyticsService.GetEvents()
+	c.JSON(http.StatusOK, paginate(c, events))
+}
+
+// GetPerformanceMetrics returns performance metrics
+// @Summary Get performance metrics
+// @Description Retrieve system performance metrics and response times
+// @Tags analytics
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Performance metrics"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/analytics/performance [get]
+func (h *Handlers) GetPerformanceMetrics(c *gin.Context) {
+	metrics := h.analyticsService.GetPerformanceMetrics()
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    metrics,
+	})
+}
+
+// TrackEvent handles custom event tracking
+// @Summary Track custom event
+// @Description Record a custom analytics event with arbitrary data
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body object{type=string,data=object} true "Event type and data"
+// @Success 200 {object} map[string]interface{} "Event tracked successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/analytics/track [post]
+func (h *Handlers) TrackEvent(c *gin.Context) {
+	var req struct {
+		Type string                 `json:"type" binding:"required"`
+		Data map[string]interface{} `json:"data"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"code":    "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	h.analyticsService.TrackEvent(userID, req.Type, req.Data)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -449,7 +1119,7 @@ func (h *Handlers) ConfigureProxy(c *gin.Context) {
 
 // GetProxyStatus returns proxy status
 // @Summary Get proxy status
-// @Description Retrieve current proxy connection status and metrics
+// @Description Retrieve current proxy connection status and pool-wide health
 // @Tags proxy
 // @Produce json
 // @Security BearerAuth
@@ -457,20 +1127,29 @@ func (h *Handlers) ConfigureProxy(c *gin.Context) {
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Router /api/v1/proxy/status [get]
 func (h *Handlers) GetProxyStatus(c *gin.Context) {
-	// This would fetch actual proxy status
+	entries := h.proxyService.ListProxyDashboard()
+
+	healthyCount := 0
+	for _, entry := range entries {
+		if entry.Healthy {
+			healthyCount++
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"enabled":   false,
-			"connected": false,
-			"latency":   0,
+			"enabled":       h.proxyService.IsProxyEnabled() || len(entries) > 0,
+			"pool_size":     len(entries),
+			"healthy_count": healthyCount,
+			"proxies":       entries,
 		},
 	})
 }
 
 // TestProxy tests proxy connection
 // @Summary Test proxy connection
-// @Description Test the current proxy configuration and connectivity
+// @Description Dispatch a live connectivity probe through the configured proxy (or a proxy drawn from the pool)
 // @Tags proxy
 // @Produce json
 // @Security BearerAuth
@@ -478,13 +1157,11 @@ func (h *Handlers) GetProxyStatus(c *gin.Context) {
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Router /api/v1/proxy/test [post]
 func (h *Handlers) TestProxy(c *gin.Context) {
-	// This would test the actual proxy
+	status := h.proxyService.TestProxy()
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data": gin.H{
-			"connected": false,
-			"message":   "Proxy testing not implemented",
-		},
+		"data":    status,
 	})
 }
 
@@ -578,21 +1255,20 @@ func (h *Handlers) GetUser(c *gin.Context) {
 
 // ListUsers lists all users
 // @Summary List all users
-// @Description Retrieve a list of all users in the system
+// @Description Retrieve a paginated, filterable list of all users in the system
 // @Tags user-management
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "List of users"
+// @Param page query int false "Page number (default 1)"
+// @Param per_page query int false "Items per page (default 100, max 1000)"
+// @Param role query string false "Filter by role"
+// @Param active query bool false "Filter by active status"
+// @Success 200 {object} map[string]interface{} "Paginated list of users"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Router /api/v1/users [get]
 func (h *Handlers) ListUsers(c *gin.Context) {
 	users := h.authService.ListUsers()
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    users,
-		"count":   len(users),
-	})
+	c.JSON(http.StatusOK, paginate(c, users))
 }
 
 // UpdateUser updates user information
@@ -700,22 +1376,22 @@ func (h *Handlers) DeleteUser(c *gin.Context) {
 	})
 }
 
-// ENHANCED PROXY MANAGEMENT HANDLERS
+// ROLE MANAGEMENT HANDLERS
 
-// CreateProxy creates a new proxy configuration
-// @Summary Create new proxy
-// @Description Add a new proxy configuration to the system
-// @Tags proxy-management
+// CreateRole creates a new role
+// @Summary Create new role
+// @Description Create a new role with a set of permissions
+// @Tags role-management
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body services.CreateProxyRequest true "Proxy configuration data"
-// @Success 201 {object} map[string]interface{} "Proxy created successfully"
+// @Param request body services.CreateRoleRequest true "Role creation data"
+// @Success 201 {object} map[string]interface{} "Role created successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request format"
-// @Failure 401 {object} map[string]interface{} "Unauthorized"
-// @Router /api/v1/proxies [post]
-func (h *Handlers) CreateProxy(c *gin.Context) {
-	var req services.CreateProxyRequest
+// @Failure 409 {object} map[string]interface{} "Role already exists"
+// @Router /api/v1/roles [post]
+func (h *Handlers) CreateRole(c *gin.Context) {
+	var req services.CreateRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request format",
@@ -725,17 +1401,16 @@ func (h *Handlers) CreateProxy(c *gin.Context) {
 		return
 	}
 
-	// Get current user from token
-	createdBy := c.GetString("username")
-	if createdBy == "" {
-		createdBy = "unknown"
-	}
-
-	proxy, err := h.proxyService.CreateProxy(req, createdBy)
+	role, err := h.authService.CreateRole(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Proxy creation failed",
-			"code":    "PROXY_CREATION_FAILED",
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "role already exists" {
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":   "Role creation failed",
+			"code":    "ROLE_CREATION_FAILED",
 			"message": err.Error(),
 		})
 		return
@@ -743,38 +1418,29 @@ func (h *Handlers) CreateProxy(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
-		"message": "Proxy created successfully",
-		"data":    proxy,
+		"message": "Role created successfully",
+		"data":    role,
 	})
 }
 
-// GetProxy gets proxy information by ID
-// @Summary Get proxy information
-// @Description Retrieve proxy configuration by ID
-// @Tags proxy-management
+// GetRole gets role information
+// @Summary Get role information
+// @Description Retrieve a role and its permissions by name
+// @Tags role-management
 // @Produce json
 // @Security BearerAuth
-// @Param proxy_id path string true "Proxy ID"
-// @Success 200 {object} map[string]interface{} "Proxy information"
-// @Failure 401 {object} map[string]interface{} "Unauthorized"
-// @Failure 404 {object} map[string]interface{} "Proxy not found"
-// @Router /api/v1/proxies/{proxy_id} [get]
-func (h *Handlers) GetProxy(c *gin.Context) {
-	proxyID := c.Param("proxy_id")
-	if proxyID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Proxy ID parameter required",
-			"code":    "MISSING_PROXY_ID",
-			"message": "Please provide a valid proxy ID",
-		})
-		return
-	}
-
-	proxy, err := h.proxyService.GetProxy(proxyID)
+// @Param name path string true "Role name"
+// @Success 200 {object} map[string]interface{} "Role information"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Router /api/v1/roles/{name} [get]
+func (h *Handlers) GetRole(c *gin.Context) {
+	name := c.Param("name")
+
+	role, err := h.authService.GetRole(name)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Proxy not found",
-			"code":    "PROXY_NOT_FOUND",
+			"error":   "Role not found",
+			"code":    "ROLE_NOT_FOUND",
 			"message": err.Error(),
 		})
 		return
@@ -782,30 +1448,262 @@ func (h *Handlers) GetProxy(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    proxy,
+		"data":    role,
 	})
 }
 
-// ListProxies lists all proxy configurations
-// @Summary List all proxies
-// @Description Retrieve a list of all proxy configurations
-// @Tags proxy-management
+// ListRoles lists all roles
+// @Summary List all roles
+// @Description Retrieve a list of all registered roles and their permissions
+// @Tags role-management
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "List of proxies"
-// @Failure 401 {object} map[string]interface{} "Unauthorized"
-// @Router /api/v1/proxies [get]
-func (h *Handlers) ListProxies(c *gin.Context) {
-	proxies := h.proxyService.ListProxies()
+// @Success 200 {object} map[string]interface{} "List of roles"
+// @Router /api/v1/roles [get]
+func (h *Handlers) ListRoles(c *gin.Context) {
+	roles := h.authService.ListRoles()
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    proxies,
-		"count":   len(proxies),
+		"data":    roles,
+		"count":   len(roles),
 	})
 }
 
-// UpdateProxy updates proxy configuration
+// UpdateRole replaces a role's permission set
+// @Summary Update role permissions
+// @Description Replace the permission set assigned to a role
+// @Tags role-management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Role name"
+// @Param request body services.UpdateRoleRequest true "Role update data"
+// @Success 200 {object} map[string]interface{} "Role updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request format"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Router /api/v1/roles/{name} [put]
+func (h *Handlers) UpdateRole(c *gin.Context) {
+	name := c.Param("name")
+
+	var req services.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"code":    "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	role, err := h.authService.UpdateRole(name, req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "role not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":   "Role update failed",
+			"code":    "ROLE_UPDATE_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Role updated successfully",
+		"data":    role,
+	})
+}
+
+// DeleteRole deletes a role
+// @Summary Delete role
+// @Description Delete a role from the system
+// @Tags role-management
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Role name"
+// @Success 200 {object} map[string]interface{} "Role deleted successfully"
+// @Failure 403 {object} map[string]interface{} "Cannot delete admin role"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Router /api/v1/roles/{name} [delete]
+func (h *Handlers) DeleteRole(c *gin.Context) {
+	name := c.Param("name")
+
+	err := h.authService.DeleteRole(name)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "role not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "cannot delete admin role" {
+			statusCode = http.StatusForbidden
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":   "Role deletion failed",
+			"code":    "ROLE_DELETION_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Role deleted successfully",
+	})
+}
+
+// ENHANCED PROXY MANAGEMENT HANDLERS
+
+// CreateProxy creates a new proxy configuration
+// @Summary Create new proxy
+// @Description Add a new proxy configuration to the system
+// @Tags proxy-management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.CreateProxyRequest true "Proxy configuration data"
+// @Success 201 {object} map[string]interface{} "Proxy created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/proxies [post]
+func (h *Handlers) CreateProxy(c *gin.Context) {
+	var req services.CreateProxyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"code":    "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// Get current user from token
+	createdBy := c.GetString("username")
+	if createdBy == "" {
+		createdBy = "unknown"
+	}
+
+	proxy, err := h.proxyService.CreateProxy(req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Proxy creation failed",
+			"code":    "PROXY_CREATION_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Proxy created successfully",
+		"data":    proxy,
+	})
+}
+
+// GetProxy gets proxy information by ID
+// @Summary Get proxy information
+// @Description Retrieve proxy configuration by ID. Supports long-polling: pass `hash` (the hash from a prior response) and `wait` (e.g. "30s") to block until the config changes or wait elapses.
+// @Tags proxy-management
+// @Produce json
+// @Security BearerAuth
+// @Param proxy_id path string true "Proxy ID"
+// @Param hash query string false "Hash from a prior response; if unchanged, the request blocks"
+// @Param wait query string false "Max long-poll duration, e.g. 30s (capped at 60s)"
+// @Success 200 {object} map[string]interface{} "Proxy information"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Proxy not found"
+// @Router /api/v1/proxies/{proxy_id} [get]
+func (h *Handlers) GetProxy(c *gin.Context) {
+	proxyID := c.Param("proxy_id")
+	if proxyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Proxy ID parameter required",
+			"code":    "MISSING_PROXY_ID",
+			"message": "Please provide a valid proxy ID",
+		})
+		return
+	}
+
+	if wait := parseLongPollWait(c.Query("wait")); wait > 0 {
+		if clientHash := c.Query("hash"); clientHash != "" {
+			if currentHash, err := h.proxyService.HashProxy(proxyID); err == nil && currentHash == clientHash {
+				h.proxyService.WaitForChange(c.Request.Context(), wait)
+			}
+		}
+	}
+
+	proxy, err := h.proxyService.GetProxy(proxyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Proxy not found",
+			"code":    "PROXY_NOT_FOUND",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	hash, _ := h.proxyService.HashProxy(proxyID)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    proxy,
+		"hash":    hash,
+	})
+}
+
+// ListProxies lists proxy configurations matching the given filters
+// @Summary List all proxies
+// @Description Retrieve a limit/offset-paginated, filterable list of proxy configurations, ordered by name then ID. Supports long-polling: pass `hash` (the hash from a prior response) and `wait` (e.g. "30s") to block until the list changes or wait elapses.
+// @Tags proxy-management
+// @Produce json
+// @Security BearerAuth
+// @Param hash query string false "Hash from a prior response; if unchanged, the request blocks"
+// @Param wait query string false "Max long-poll duration, e.g. 30s (capped at 60s)"
+// @Param limit query int false "Max results to return (default 100, max 1000)"
+// @Param offset query int false "Results to skip"
+// @Param id query []string false "Restrict to these proxy IDs (repeatable)"
+// @Param name query string false "Filter by name (substring)"
+// @Param host query string false "Filter by host (substring)"
+// @Param active query bool false "Filter by active status"
+// @Param assigned query bool false "Filter by whether >=1 user is directly assigned"
+// @Param unhealthy query bool false "Filter by failed-health-check status"
+// @Success 200 {object} map[string]interface{} "Page of proxies"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/proxies [get]
+func (h *Handlers) ListProxies(c *gin.Context) {
+	if wait := parseLongPollWait(c.Query("wait")); wait > 0 {
+		if clientHash := c.Query("hash"); clientHash != "" && h.proxyService.HashProxies() == clientHash {
+			h.proxyService.WaitForChange(c.Request.Context(), wait)
+		}
+	}
+
+	limit, offset := parseLimitOffset(c)
+	proxies, total := h.proxyService.QueryProxies(services.QueryProxiesOptions{
+		IDs:       c.QueryArray("id"),
+		Name:      c.Query("name"),
+		Host:      c.Query("host"),
+		Active:    parseOptionalBool(c.Query("active")),
+		Assigned:  parseOptionalBool(c.Query("assigned")),
+		Unhealthy: parseOptionalBool(c.Query("unhealthy")),
+		Limit:     limit,
+		Offset:    offset,
+	})
+
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    proxies,
+		"count":   len(proxies),
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"hash":    h.proxyService.HashProxies(),
+	})
+}
+
+// UpdateProxy updates proxy configuration
 // @Summary Update proxy configuration
 // @Description Update proxy settings like credentials, host, port, etc.
 // @Tags proxy-management
@@ -890,7 +1788,7 @@ func (h *Handlers) DeleteProxy(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "proxy not found" {
 			statusCode = http.StatusNotFound
-		} else if err.Error() == "cannot delete default proxy" {
+		} else if err.Error() == "cannot delete default proxy" || err.Error() == "cannot delete a proxy managed by the proxies config file" {
 			statusCode = http.StatusForbidden
 		}
 
@@ -932,11 +1830,17 @@ func (h *Handlers) AssignProxyToUser(c *gin.Context) {
 		return
 	}
 
-	err := h.proxyService.AssignProxyToUser(req.Username, req.ProxyID)
+	err := h.proxyService.AssignProxyToUser(req.Username, req.ProxyID, req.GroupID, req.NoProxy)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err.Error() == "proxy not found" || err.Error() == "proxy is not active" {
+		switch err.Error() {
+		case "proxy not found", "proxy is not active", "proxy group not found",
+			"exactly one of proxy_id or group_id must be set":
 			statusCode = http.StatusBadRequest
+		default:
+			if strings.HasPrefix(err.Error(), "invalid no_proxy entry") {
+				statusCode = http.StatusBadRequest
+			}
 		}
 
 		c.JSON(statusCode, gin.H{
@@ -1028,21 +1932,663 @@ func (h *Handlers) GetUserProxy(c *gin.Context) {
 	})
 }
 
-// ListUserProxyAssignments lists all user-proxy assignments
+// ResolveUserProxy reports whether a target URL should bypass a user's
+// assigned proxy
+// @Summary Resolve whether a URL bypasses a user's assigned proxy
+// @Description Checks a target URL against the user's no_proxy override (if any) and the assigned proxy's own no_proxy list, without making a request.
+// @Tags proxy-management
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "Username"
+// @Param url query string true "Target URL to check"
+// @Success 200 {object} map[string]interface{} "Resolution result"
+// @Failure 400 {object} map[string]interface{} "Missing or invalid url parameter"
+// @Failure 404 {object} map[string]interface{} "User or proxy not found"
+// @Router /api/v1/users/{username}/proxy/resolve [get]
+func (h *Handlers) ResolveUserProxy(c *gin.Context) {
+	username := c.Param("username")
+	targetURL := c.Query("url")
+	if targetURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "url query parameter required",
+			"code":    "MISSING_URL",
+			"message": "Please provide a url to resolve",
+		})
+		return
+	}
+
+	resolution, err := h.proxyService.ResolveUserProxy(username, targetURL)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if strings.HasPrefix(err.Error(), "invalid target url") {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{
+			"error":   "Proxy resolution failed",
+			"code":    "PROXY_RESOLUTION_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resolution,
+	})
+}
+
+// ListUserProxyAssignments lists user-proxy assignments matching the given filters
 // @Summary List user-proxy assignments
-// @Description Retrieve all current user-proxy assignments in the system
+// @Description Retrieve a limit/offset-paginated, filterable list of user-proxy assignments, ordered by username
 // @Tags proxy-management
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "List of user-proxy assignments"
+// @Param limit query int false "Max results to return (default 100, max 1000)"
+// @Param offset query int false "Results to skip"
+// @Param username query string false "Filter by username (prefix)"
+// @Param proxy_id query string false "Filter by the resolved proxy ID"
+// @Param group_id query string false "Filter by assigned proxy group ID"
+// @Success 200 {object} map[string]interface{} "Page of user-proxy assignments"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Router /api/v1/proxy-assignments [get]
 func (h *Handlers) ListUserProxyAssignments(c *gin.Context) {
-	assignments := h.proxyService.ListUserProxyAssignments()
+	limit, offset := parseLimitOffset(c)
+	assignments, total := h.proxyService.QueryUserProxyAssignments(services.QueryAssignmentsOptions{
+		Username: c.Query("username"),
+		ProxyID:  c.Query("proxy_id"),
+		GroupID:  c.Query("group_id"),
+		Limit:    limit,
+		Offset:   offset,
+	})
 
+	c.Header("X-Total-Count", strconv.Itoa(total))
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    assignments,
 		"count":   len(assignments),
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// GetProxyDashboard gets a single proxy's live health and traffic stats
+// @Summary Get proxy dashboard detail
+// @Description Retrieve a proxy's configuration plus live health and traffic counters
+// @Tags proxy-dashboard
+// @Produce json
+// @Security BearerAuth
+// @Param proxy_id path string true "Proxy ID"
+// @Success 200 {object} map[string]interface{} "Proxy dashboard detail"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Proxy not found"
+// @Router /api/v1/proxy-stats/{proxy_id} [get]
+func (h *Handlers) GetProxyDashboard(c *gin.Context) {
+	proxyID := c.Param("proxy_id")
+	if proxyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Proxy ID parameter required",
+			"code":    "MISSING_PROXY_ID",
+			"message": "Please provide a valid proxy ID",
+		})
+		return
+	}
+
+	entry, err := h.proxyService.GetProxyDashboard(proxyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Proxy not found",
+			"code":    "PROXY_NOT_FOUND",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// ListProxyDashboard lists every proxy's live health and traffic stats
+// @Summary List proxy dashboard overview
+// @Description Retrieve every proxy's configuration plus live health and traffic counters
+// @Tags proxy-dashboard
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Proxy dashboard overview"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/proxy-stats [get]
+func (h *Handlers) ListProxyDashboard(c *gin.Context) {
+	entries := h.proxyService.ListProxyDashboard()
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+		"count":   len(entries),
+	})
+}
+
+// GetProxyTraffic gets a proxy's traffic-over-time series
+// @Summary Get proxy traffic history
+// @Description Retrieve a proxy's per-minute traffic and latency history
+// @Tags proxy-dashboard
+// @Produce json
+// @Security BearerAuth
+// @Param proxy_id path string true "Proxy ID"
+// @Param window query int false "Number of minutes of history to return (default 60, max 60)"
+// @Success 200 {object} map[string]interface{} "Proxy traffic history"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Proxy not found"
+// @Router /api/v1/proxy-stats/{proxy_id}/traffic [get]
+func (h *Handlers) GetProxyTraffic(c *gin.Context) {
+	proxyID := c.Param("proxy_id")
+	if proxyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Proxy ID parameter required",
+			"code":    "MISSING_PROXY_ID",
+			"message": "Please provide a valid proxy ID",
+		})
+		return
+	}
+
+	window := 60
+	if raw := c.Query("window"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid window parameter",
+				"code":    "INVALID_WINDOW",
+				"message": "window must be a positive integer number of minutes",
+			})
+			return
+		}
+		window = parsed
+	}
+
+	points, err := h.proxyService.GetProxyTraffic(proxyID, window)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Proxy not found",
+			"code":    "PROXY_NOT_FOUND",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    points,
 	})
+}
+
+// GetProxyHealth gets a proxy's rolling-window health-check and traffic report
+// @Summary Get proxy health
+// @Description Retrieve a proxy's current health state plus a rolling window of its success rate, captcha count, and latency
+// @Tags proxy-dashboard
+// @Produce json
+// @Security BearerAuth
+// @Param proxy_id path string true "Proxy ID"
+// @Param window query int false "Number of minutes of history to average over (default 5, max 60)"
+// @Success 200 {object} map[string]interface{} "Proxy health report"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Proxy not found"
+// @Router /api/v1/proxies/{proxy_id}/health [get]
+func (h *Handlers) GetProxyHealth(c *gin.Context) {
+	proxyID := c.Param("proxy_id")
+	if proxyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Proxy ID parameter required",
+			"code":    "MISSING_PROXY_ID",
+			"message": "Please provide a valid proxy ID",
+		})
+		return
+	}
+
+	window := 5
+	if raw := c.Query("window"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid window parameter",
+				"code":    "INVALID_WINDOW",
+				"message": "window must be a positive integer number of minutes",
+			})
+			return
+		}
+		window = parsed
+	}
+
+	report, err := h.proxyService.GetProxyHealth(proxyID, window)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Proxy not found",
+			"code":    "PROXY_NOT_FOUND",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// DrainProxy stops a proxy from being picked for new requests
+// @Summary Drain a proxy
+// @Description Stop assigning new requests to a proxy while in-flight requests finish on their own
+// @Tags proxy-dashboard
+// @Produce json
+// @Security BearerAuth
+// @Param proxy_id path string true "Proxy ID"
+// @Success 200 {object} map[string]interface{} "Proxy draining"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Proxy not found"
+// @Router /api/v1/proxies/{proxy_id}/drain [post]
+func (h *Handlers) DrainProxy(c *gin.Context) {
+	proxyID := c.Param("proxy_id")
+	if proxyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Proxy ID parameter required",
+			"code":    "MISSING_PROXY_ID",
+			"message": "Please provide a valid proxy ID",
+		})
+		return
+	}
+
+	if err := h.proxyService.DrainProxy(proxyID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Proxy not found",
+			"code":    "PROXY_NOT_FOUND",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Proxy is now draining; it will stop receiving new requests",
+	})
+}
+
+// defaultDelayTimeoutMs is the `timeout` query default (in milliseconds)
+// for TestProxyDelay/TestProxyDelayBulk.
+const defaultDelayTimeoutMs = 5000
+
+// parseDelayParams reads the shared `url`/`timeout` query params for the
+// delay-probe endpoints, defaulting timeout to defaultDelayTimeoutMs.
+func parseDelayParams(c *gin.Context) (testURL string, timeout time.Duration, err error) {
+	testURL = c.Query("url")
+
+	timeoutMs := defaultDelayTimeoutMs
+	if raw := c.Query("timeout"); raw != "" {
+		parsed, parseErr := strconv.Atoi(raw)
+		if parseErr != nil || parsed <= 0 {
+			return "", 0, fmt.Errorf("timeout must be a positive integer number of milliseconds")
+		}
+		timeoutMs = parsed
+	}
+
+	return testURL, time.Duration(timeoutMs) * time.Millisecond, nil
+}
+
+// TestProxyDelay runs a Clash-style delay probe against one proxy
+// @Summary Test a proxy's delay
+// @Description GET a test URL through the proxy's upstream and report the round-trip latency, caching the result for ListProxies
+// @Tags proxy-dashboard
+// @Produce json
+// @Security BearerAuth
+// @Param proxy_id path string true "Proxy ID"
+// @Param url query string false "URL to probe (default http://cp.cloudflare.com/generate_204)"
+// @Param timeout query int false "Probe timeout in milliseconds (default 5000)"
+// @Success 200 {object} map[string]interface{} "Delay-probe result"
+// @Failure 400 {object} map[string]interface{} "Invalid request format"
+// @Failure 404 {object} map[string]interface{} "Proxy not found"
+// @Router /api/v1/proxies/{proxy_id}/delay [get]
+func (h *Handlers) TestProxyDelay(c *gin.Context) {
+	proxyID := c.Param("proxy_id")
+	if proxyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Proxy ID parameter required",
+			"code":    "MISSING_PROXY_ID",
+			"message": "Please provide a valid proxy ID",
+		})
+		return
+	}
+
+	testURL, timeout, err := parseDelayParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid timeout parameter",
+			"code":    "INVALID_TIMEOUT",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.proxyService.TestProxyDelay(proxyID, testURL, timeout)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Proxy not found",
+			"code":    "PROXY_NOT_FOUND",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// TestProxyDelayRequest is the body of the bulk proxy delay-test endpoint
+type TestProxyDelayRequest struct {
+	ProxyIDs []string `json:"proxy_ids" binding:"required"`
+}
+
+// TestProxyDelayBulk runs a Clash-style delay probe against several proxies
+// @Summary Test several proxies' delay
+// @Description GET a test URL through each listed proxy's upstream and report each one's round-trip latency, caching every result for ListProxies
+// @Tags proxy-dashboard
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param url query string false "URL to probe (default http://cp.cloudflare.com/generate_204)"
+// @Param timeout query int false "Probe timeout in milliseconds (default 5000)"
+// @Param request body TestProxyDelayRequest true "Proxy IDs to probe"
+// @Success 200 {object} map[string]interface{} "Delay-probe results"
+// @Failure 400 {object} map[string]interface{} "Invalid request format"
+// @Router /api/v1/proxies/delay [post]
+func (h *Handlers) TestProxyDelayBulk(c *gin.Context) {
+	var req TestProxyDelayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"code":    "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	testURL, timeout, err := parseDelayParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid timeout parameter",
+			"code":    "INVALID_TIMEOUT",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	results := h.proxyService.TestProxyDelayBulk(req.ProxyIDs, testURL, timeout)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    results,
+	})
+}
+
+// ReloadProxies re-reads the proxies.yaml config file and diffs it against
+// the live pool
+// @Summary Reload config-file proxies
+// @Description Re-read the proxies.yaml config file and add/update/remove file-provisioned proxies to match, without disturbing API-created proxies or in-flight assignments
+// @Tags proxy-management
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Reload result"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "No proxies config file configured, or it failed to parse"
+// @Router /api/v1/proxies/reload [post]
+func (h *Handlers) ReloadProxies(c *gin.Context) {
+	added, updated, removed, err := h.proxyService.ReloadProxiesFromFile()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Proxy reload failed",
+			"code":    "PROXY_RELOAD_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"added":   added,
+			"updated": updated,
+			"removed": removed,
+		},
+	})
+}
+
+// CreateProxyGroup creates a new proxy group
+// @Summary Create a proxy group
+// @Description Create a named group of proxies resolved to one concrete member per lookup according to a strategy (select, round-robin, random, fallback, url-test)
+// @Tags proxy-management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.CreateProxyGroupRequest true "Proxy group data"
+// @Success 201 {object} map[string]interface{} "Proxy group created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/proxy-groups [post]
+func (h *Handlers) CreateProxyGroup(c *gin.Context) {
+	var req services.CreateProxyGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"code":    "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	createdBy := c.GetString("username")
+	if createdBy == "" {
+		createdBy = "unknown"
+	}
+
+	group, err := h.proxyService.CreateProxyGroup(req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Proxy group creation failed",
+			"code":    "PROXY_GROUP_CREATION_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Proxy group created successfully",
+		"data":    group,
+	})
+}
+
+// GetProxyGroup gets a proxy group by ID
+// @Summary Get a proxy group
+// @Tags proxy-management
+// @Produce json
+// @Security BearerAuth
+// @Param group_id path string true "Proxy group ID"
+// @Success 200 {object} map[string]interface{} "Proxy group information"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Proxy group not found"
+// @Router /api/v1/proxy-groups/{group_id} [get]
+func (h *Handlers) GetProxyGroup(c *gin.Context) {
+	group, err := h.proxyService.GetProxyGroup(c.Param("group_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Proxy group not found",
+			"code":    "PROXY_GROUP_NOT_FOUND",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    group,
+	})
+}
+
+// ListProxyGroups lists all proxy groups
+// @Summary List all proxy groups
+// @Tags proxy-management
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "List of proxy groups"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/proxy-groups [get]
+func (h *Handlers) ListProxyGroups(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.proxyService.ListProxyGroups(),
+	})
+}
+
+// UpdateProxyGroup updates a proxy group's members/strategy
+// @Summary Update a proxy group
+// @Tags proxy-management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param group_id path string true "Proxy group ID"
+// @Param request body services.UpdateProxyGroupRequest true "Proxy group update data"
+// @Success 200 {object} map[string]interface{} "Proxy group updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Proxy group not found"
+// @Router /api/v1/proxy-groups/{group_id} [put]
+func (h *Handlers) UpdateProxyGroup(c *gin.Context) {
+	var req services.UpdateProxyGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"code":    "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	group, err := h.proxyService.UpdateProxyGroup(c.Param("group_id"), req)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "proxy group not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{
+			"error":   "Proxy group update failed",
+			"code":    "PROXY_GROUP_UPDATE_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Proxy group updated successfully",
+		"data":    group,
+	})
+}
+
+// DeleteProxyGroup deletes a proxy group
+// @Summary Delete a proxy group
+// @Tags proxy-management
+// @Produce json
+// @Security BearerAuth
+// @Param group_id path string true "Proxy group ID"
+// @Success 200 {object} map[string]interface{} "Proxy group deleted successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Proxy group not found"
+// @Router /api/v1/proxy-groups/{group_id} [delete]
+func (h *Handlers) DeleteProxyGroup(c *gin.Context) {
+	if err := h.proxyService.DeleteProxyGroup(c.Param("group_id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Proxy group not found",
+			"code":    "PROXY_GROUP_NOT_FOUND",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Proxy group deleted successfully",
+	})
+}
+
+// SelectProxyGroupMember pins a "select"-strategy group to one member
+// @Summary Pin a select-strategy proxy group to one member
+// @Description Only valid for groups created with strategy "select"; other strategies resolve their member automatically on each lookup.
+// @Tags proxy-management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param group_id path string true "Proxy group ID"
+// @Param request body services.SelectProxyGroupMemberRequest true "Member to select"
+// @Success 200 {object} map[string]interface{} "Proxy group member selected successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Proxy group not found"
+// @Router /api/v1/proxy-groups/{group_id}/select [put]
+func (h *Handlers) SelectProxyGroupMember(c *gin.Context) {
+	var req services.SelectProxyGroupMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"code":    "INVALID_REQUEST",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := h.proxyService.SelectProxyGroupMember(c.Param("group_id"), req.ProxyID); err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "proxy group not found" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{
+			"error":   "Proxy group member selection failed",
+			"code":    "PROXY_GROUP_SELECT_FAILED",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Proxy group member selected successfully",
+	})
+}
+
+// maxLongPollWait bounds how long a GetProxy/ListProxies long-poll request
+// may hold the connection open.
+const maxLongPollWait = 60 * time.Second
+
+// parseLongPollWait parses a `wait` query duration (e.g. "30s"), clamped to
+// maxLongPollWait. An empty or invalid value disables long-polling.
+func parseLongPollWait(raw string) time.Duration {
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	if d > maxLongPollWait {
+		return maxLongPollWait
+	}
+	return d
+}
+
+// domainFromURL extracts the host for use as the analytics asin_domain label
+func domainFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "unknown"
+	}
+	return parsed.Hostname()
 } 
\ No newline at end of file
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultPerPage and maxPerPage bound the per_page query parameter accepted
+// by every paginated list endpoint.
+const (
+	defaultPerPage = 100
+	maxPerPage     = 1000
+)
+
+// paginate filters items (a slice of structs or struct pointers) by any
+// query parameter that names one of the item type's JSON fields, then
+// slices the result into the page requested via page/per_page. It returns
+// the envelope every paginated list endpoint in this package shares:
+// data/total/page/per_page/next_page (next_page is 0 once exhausted),
+// mirroring the cursor-style pagination of the Traefik dashboard API. It
+// also sets an X-Next-Page response header as a hint for clients that only
+// look at headers.
+func paginate(c *gin.Context, items interface{}) gin.H {
+	filtered := filterByQuery(items, c.Request.URL.Query())
+	page, perPage := paginationParams(c)
+
+	total := filtered.Len()
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	nextPage := 0
+	if end < total {
+		nextPage = page + 1
+		c.Header("X-Next-Page", strconv.Itoa(nextPage))
+	}
+
+	return gin.H{
+		"success":   true,
+		"data":      filtered.Slice(start, end).Interface(),
+		"total":     total,
+		"page":      page,
+		"per_page":  perPage,
+		"next_page": nextPage,
+	}
+}
+
+// parseLimitOffset reads limit/offset from the query string for the
+// limit/offset-style list endpoints (as opposed to paginate's page/per_page
+// style), applying the same defaultPerPage/maxPerPage bounds.
+func parseLimitOffset(c *gin.Context) (limit, offset int) {
+	limit = defaultPerPage
+	if v, err := strconv.Atoi(c.Query("limit")); err == nil && v > 0 {
+		limit = v
+	}
+	if limit > maxPerPage {
+		limit = maxPerPage
+	}
+
+	if v, err := strconv.Atoi(c.Query("offset")); err == nil && v > 0 {
+		offset = v
+	}
+
+	return limit, offset
+}
+
+// parseOptionalBool parses a query parameter as a bool, returning nil
+// (meaning "filter not set") when raw is empty or not a valid bool.
+func parseOptionalBool(raw string) *bool {
+	if raw == "" {
+		return nil
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
+
+// paginationParams reads page/per_page from the query string, defaulting to
+// page 1 and defaultPerPage, and capping per_page at maxPerPage.
+func paginationParams(c *gin.Context) (page, perPage int) {
+	page = 1
+	if v, err := strconv.Atoi(c.Query("page")); err == nil && v > 0 {
+		page = v
+	}
+
+	perPage = defaultPerPage
+	if v, err := strconv.Atoi(c.Query("per_page")); err == nil && v > 0 {
+		perPage = v
+	}
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	return page, perPage
+}
+
+// filterByQuery returns the subset of items whose JSON-tagged fields match
+// every query parameter other than the pagination controls (page/per_page).
+// A query parameter that doesn't name a field on the item type is ignored,
+// so callers never need to list which params are filterable.
+func filterByQuery(items interface{}, query url.Values) reflect.Value {
+	value := reflect.ValueOf(items)
+
+	filters := make(map[string]string, len(query))
+	for key, values := range query {
+		if key == "page" || key == "per_page" || len(values) == 0 {
+			continue
+		}
+		filters[key] = values[0]
+	}
+	if len(filters) == 0 {
+		return value
+	}
+
+	result := reflect.MakeSlice(value.Type(), 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		if matchesFilters(value.Index(i), filters) {
+			result = reflect.Append(result, value.Index(i))
+		}
+	}
+	return result
+}
+
+// matchesFilters reports whether item (a struct or pointer to struct)
+// matches every filter, comparing each filter's value against the field
+// whose JSON tag it names.
+func matchesFilters(item reflect.Value, filters map[string]string) bool {
+	for item.Kind() == reflect.Ptr {
+		item = item.Elem()
+	}
+	if item.Kind() != reflect.Struct {
+		return true
+	}
+
+	t := item.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonFieldName(t.Field(i).Tag.Get("json"))
+		want, ok := filters[name]
+		if !ok {
+			continue
+		}
+		if fmt.Sprintf("%v", item.Field(i).Interface()) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonFieldName strips trailing options (e.g. ",omitempty") from a json
+// struct tag, leaving just the field name.
+func jsonFieldName(tag string) string {
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
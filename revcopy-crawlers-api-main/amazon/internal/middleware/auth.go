@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -8,7 +9,10 @@ import (
 	"github.com/revcopy/crawlers/amazon/internal/services"
 )
 
-// AuthRequired middleware validates JWT tokens
+// AuthRequired middleware validates either a JWT or a "pat_"-prefixed
+// personal access token, populating the same context keys either way so
+// downstream handlers and RequirePermission don't need to care which one
+// was used.
 func AuthRequired(authService *services.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -35,6 +39,28 @@ func AuthRequired(authService *services.AuthService) gin.HandlerFunc {
 		}
 
 		token := parts[1]
+		if services.IsPAT(token) {
+			pat, err := authService.ValidatePAT(token)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error":   "Invalid or expired token",
+					"code":    "TOKEN_INVALID",
+					"message": err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			c.Set("user_id", pat.UserID)
+			c.Set("username", pat.Username)
+			c.Set("permissions", pat.Scopes)
+			if user, err := authService.GetUser(pat.Username); err == nil {
+				c.Set("plan", user.Plan)
+			}
+			c.Next()
+			return
+		}
+
 		claims, err := authService.ValidateToken(token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
@@ -49,6 +75,35 @@ func AuthRequired(authService *services.AuthService) gin.HandlerFunc {
 		// Store user information in context
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("role", claims.Role)
+		c.Set("plan", claims.Plan)
+		c.Set("permissions", claims.Permissions)
+		c.Set("jti", claims.ID)
+		c.Set("token_expires_at", claims.ExpiresAt.Time)
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
+
+// RequirePermission returns a middleware that rejects the request with 403
+// unless the authenticated user's JWT claims (set by AuthRequired) include
+// perm. It must be chained after AuthRequired.
+func RequirePermission(perm string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions, _ := c.Get("permissions")
+		granted, _ := permissions.([]string)
+
+		for _, p := range granted {
+			if p == perm {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Insufficient permissions",
+			"code":    "PERMISSION_DENIED",
+			"message": fmt.Sprintf("this action requires the %q permission", perm),
+		})
+		c.Abort()
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// KeyOptions configures how RateLimitTier derives a per-client key from a
+// request. Masking IPv6 addresses down to a prefix keeps an attacker from
+// bypassing the limiter by rotating through their /64 (a single
+// residential allocation); TrustedProxies restricts X-Forwarded-For/
+// X-Real-IP to requests that actually came from a known proxy, so a
+// direct client can't spoof its way to someone else's bucket.
+type KeyOptions struct {
+	// IPv4MaskBits masks an IPv4 client IP before it's used as a key;
+	// 32 (the default) applies no masking.
+	IPv4MaskBits int
+	// IPv6MaskBits masks an IPv6 client IP before it's used as a key;
+	// defaults to 64, the size of the prefix most ISPs hand out per
+	// customer.
+	IPv6MaskBits int
+	// TrustedProxies lists the CIDRs allowed to set X-Forwarded-For/
+	// X-Real-IP; a request whose RemoteAddr falls outside all of them is
+	// keyed on RemoteAddr regardless of what headers it sends.
+	TrustedProxies []*net.IPNet
+}
+
+// DefaultKeyOptions applies no IP masking and trusts no proxies, matching
+// the behavior before per-client keying existed.
+func DefaultKeyOptions() KeyOptions {
+	return KeyOptions{IPv4MaskBits: 32, IPv6MaskBits: 64}
+}
+
+// ParseTrustedProxies parses a list of CIDR strings (e.g.
+// ["10.0.0.0/8", "172.16.0.0/12"]), skipping and logging any that don't
+// parse rather than failing the whole list over one typo.
+func ParseTrustedProxies(cidrs []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, raw := range cidrs {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(raw)
+		if err != nil {
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets
+}
+
+// clientKey derives the key RateLimitTier uses for req, trusting
+// X-Forwarded-For/X-Real-IP only when req's RemoteAddr is covered by one
+// of opts.TrustedProxies, then masking the resolved IP per opts.
+func clientKey(req *http.Request, opts KeyOptions) string {
+	ip := resolveClientIP(req, opts.TrustedProxies)
+	if ip == nil {
+		return "unknown"
+	}
+
+	return maskIP(ip, opts).String()
+}
+
+// resolveClientIP returns req's real client IP: RemoteAddr's host, unless
+// it's covered by trustedProxies, in which case the first address named
+// by X-Forwarded-For (or X-Real-IP) is trusted instead.
+func resolveClientIP(req *http.Request, trustedProxies []*net.IPNet) net.IP {
+	remoteHost := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(remoteHost); err == nil {
+		remoteHost = host
+	}
+	remoteIP := net.ParseIP(remoteHost)
+
+	if remoteIP == nil || !trusted(remoteIP, trustedProxies) {
+		return remoteIP
+	}
+
+	if xff := req.Header.Get("X-Forwarded-For"); xff != "" {
+		first := strings.TrimSpace(strings.Split(xff, ",")[0])
+		if ip := net.ParseIP(first); ip != nil {
+			return ip
+		}
+	}
+	if xri := req.Header.Get("X-Real-IP"); xri != "" {
+		if ip := net.ParseIP(strings.TrimSpace(xri)); ip != nil {
+			return ip
+		}
+	}
+
+	return remoteIP
+}
+
+// trusted reports whether ip falls inside any of proxies.
+func trusted(ip net.IP, proxies []*net.IPNet) bool {
+	for _, proxy := range proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskIP masks ip to opts.IPv4MaskBits or opts.IPv6MaskBits, per its
+// family. A zero mask width falls back to no masking (32/128) so a
+// caller that leaves KeyOptions unset behaves like the un-masked default.
+func maskIP(ip net.IP, opts KeyOptions) net.IP {
+	if v4 := ip.To4(); v4 != nil {
+		bits := opts.IPv4MaskBits
+		if bits <= 0 || bits > 32 {
+			bits = 32
+		}
+		return v4.Mask(net.CIDRMask(bits, 32))
+	}
+
+	bits := opts.IPv6MaskBits
+	if bits <= 0 || bits > 128 {
+		bits = 64
+	}
+	return ip.Mask(net.CIDRMask(bits, 128))
+}
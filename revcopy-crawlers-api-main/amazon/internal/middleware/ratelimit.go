@@ -1,77 +1,349 @@
 package middleware
 
 import (
+	"container/list"
+	"context"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/time/rate"
 )
 
-// RateLimiter stores rate limiters for different IPs
+// defaultIdleTTL and defaultMaxEntries are NewInMemoryStore's defaults; see
+// NewInMemoryStoreWithLimits to override them from config.
+const (
+	defaultIdleTTL    = 10 * time.Minute
+	defaultMaxEntries = 100_000
+)
+
+// Store records a hit for key within a fixed window and returns the
+// window's updated count together with its remaining TTL, so RateLimiter
+// can implement fixed-window limiting without knowing how counts are
+// persisted. A Store must be safe for concurrent use.
+type Store interface {
+	Hit(ctx context.Context, key string, window time.Duration) (count int, remainingTTL time.Duration, err error)
+}
+
+// InMemoryStore is the default Store: a per-process fixed-window counter
+// map, capped at maxEntries with least-recently-used eviction and swept
+// for entries idle past idleTTL, so neither a steady trickle of one-off
+// visitors nor a burst of an attacker cycling source IPs grows it
+// unbounded. It only limits requests landing on the same instance, so a
+// client behind a load balancer with multiple replicas can evade it by
+// spreading requests across instances; use RedisStore to share counts
+// across replicas.
+type InMemoryStore struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List // front = most recently used, back = least
+	idleTTL    time.Duration
+	maxEntries int
+}
+
+type memWindow struct {
+	key      string
+	start    time.Time
+	count    int
+	lastSeen time.Time
+}
+
+// NewInMemoryStore creates an InMemoryStore with the default idle TTL (10
+// minutes) and entry cap (100,000); see NewInMemoryStoreWithLimits to
+// override either.
+func NewInMemoryStore() *InMemoryStore {
+	return NewInMemoryStoreWithLimits(defaultIdleTTL, defaultMaxEntries)
+}
+
+// NewInMemoryStoreWithLimits creates an InMemoryStore that evicts entries
+// idle longer than idleTTL, and never holds more than maxEntries at once
+// (evicting the least recently used past the cap). maxEntries <= 0 means
+// no cap.
+func NewInMemoryStoreWithLimits(idleTTL time.Duration, maxEntries int) *InMemoryStore {
+	return &InMemoryStore{
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		idleTTL:    idleTTL,
+		maxEntries: maxEntries,
+	}
+}
+
+// Hit implements Store.
+func (s *InMemoryStore) Hit(_ context.Context, key string, window time.Duration) (int, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	elem, exists := s.entries[key]
+	var w *memWindow
+	if exists {
+		w = elem.Value.(*memWindow)
+		if now.Sub(w.start) >= window {
+			w.start = now
+			w.count = 0
+		}
+		s.lru.MoveToFront(elem)
+	} else {
+		w = &memWindow{key: key, start: now}
+		s.entries[key] = s.lru.PushFront(w)
+		s.evictOverCap()
+	}
+	w.count++
+	w.lastSeen = now
+
+	return w.count, window - now.Sub(w.start), nil
+}
+
+// evictOverCap evicts least-recently-used entries until the store is back
+// within maxEntries. Callers must hold s.mu.
+func (s *InMemoryStore) evictOverCap() {
+	for s.maxEntries > 0 && len(s.entries) > s.maxEntries {
+		back := s.lru.Back()
+		if back == nil {
+			return
+		}
+		s.lru.Remove(back)
+		delete(s.entries, back.Value.(*memWindow).key)
+	}
+}
+
+// sweep evicts entries idle longer than idleTTL. Since every touched entry
+// is moved to the front of the LRU list, the list stays ordered by
+// lastSeen, so sweep can stop at the first entry that isn't idle yet.
+func (s *InMemoryStore) sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for back := s.lru.Back(); back != nil; back = s.lru.Back() {
+		w := back.Value.(*memWindow)
+		if now.Sub(w.lastSeen) < s.idleTTL {
+			return
+		}
+		s.lru.Remove(back)
+		delete(s.entries, w.key)
+	}
+}
+
+// ratelimitIncrExpireScript atomically increments the counter for a
+// window-scoped key and, only on the key's first hit, sets its expiry to
+// the window length - so a burst of concurrent first hits can't each reset
+// the TTL, and a key always expires with its window rather than lingering.
+var ratelimitIncrExpireScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// RedisStore is a Store backed by Redis INCR+PEXPIRE, keyed
+// "ratelimit:{key}:{window-start}" so every replica sharing the same Redis
+// instance sees the same count. It falls back to an in-memory Store
+// whenever Redis returns an error, so a Redis outage degrades to
+// per-process limiting instead of failing open or blocking all traffic.
+type RedisStore struct {
+	client   *redis.Client
+	fallback *InMemoryStore
+}
+
+// NewRedisStore wraps client, using it for every Hit unless Redis is
+// unreachable.
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client, fallback: NewInMemoryStore()}
+}
+
+// Hit implements Store.
+func (s *RedisStore) Hit(ctx context.Context, key string, window time.Duration) (int, time.Duration, error) {
+	bucket := time.Now().Truncate(window).UnixMilli()
+	redisKey := fmt.Sprintf("ratelimit:%s:%d", key, bucket)
+
+	count, err := ratelimitIncrExpireScript.Run(ctx, s.client, []string{redisKey}, window.Milliseconds()).Int()
+	if err != nil {
+		log.Printf("ratelimit: redis unreachable, falling back to in-memory store: %v", err)
+		return s.fallback.Hit(ctx, key, window)
+	}
+
+	ttl, err := s.client.PTTL(ctx, redisKey).Result()
+	if err != nil || ttl < 0 {
+		ttl = window
+	}
+
+	return count, ttl, nil
+}
+
+// RateLimiter limits requests per key (typically client IP) to burst hits
+// per window, where window is how long it would take a token-bucket
+// limiter with rate r to refill burst tokens - approximating that token
+// bucket as a fixed-window counter so the count can live in a Store shared
+// across replicas.
 type RateLimiter struct {
-	limiters map[string]*rate.Limiter
-	mu       sync.RWMutex
-	rate     rate.Limit
-	burst    int
-}
-
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(r rate.Limit, b int) *RateLimiter {
-	return &RateLimiter{
-		limiters: make(map[string]*rate.Limiter),
-		rate:     r,
-		burst:    b,
+	store  Store
+	limit  int
+	window time.Duration
+}
+
+// NewRateLimiter creates a RateLimiter backed by store, allowing burst
+// requests per key every window (see RateLimiter).
+func NewRateLimiter(store Store, r rate.Limit, burst int) *RateLimiter {
+	window := time.Duration(float64(burst) / float64(r) * float64(time.Second))
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	return &RateLimiter{store: store, limit: burst, window: window}
+}
+
+// Result carries a RateLimiter check's outcome, with enough metadata for a
+// caller to populate the standard X-RateLimit-* (and, once denied,
+// Retry-After) response headers.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// Allow reports whether a request from key should proceed, along with the
+// snapshot metadata needed for rate limit response headers. A Store error
+// fails open (the request is allowed, with a zeroed Result) rather than
+// blocking traffic on a store outage.
+func (rl *RateLimiter) Allow(ctx context.Context, key string) Result {
+	count, ttl, err := rl.store.Hit(ctx, key, rl.window)
+	if err != nil {
+		return Result{Allowed: true, Limit: rl.limit, Remaining: rl.limit, ResetAt: time.Now().Add(rl.window)}
 	}
+
+	remaining := rl.limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return Result{
+		Allowed:   count <= rl.limit,
+		Limit:     rl.limit,
+		Remaining: remaining,
+		ResetAt:   time.Now().Add(ttl),
+	}
+}
+
+// TierOptions configures one named rate limit tier: its own rate/burst so
+// e.g. an "auth" tier guarding /auth/login against credential stuffing can
+// be far stricter than a "public" tier on read-only endpoints, without the
+// two sharing a bucket; and its own KeyOptions for deriving a per-client
+// key (see KeyOptions).
+type TierOptions struct {
+	RequestsPerMinute int
+	BurstSize         int
+	Key               KeyOptions
 }
 
-// GetLimiter returns a rate limiter for the given IP
-func (rl *RateLimiter) GetLimiter(ip string) *rate.Limiter {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+// RateLimitTier builds a rate limiting middleware for one named tier, with
+// its own RateLimiter instance and cleanup ticker so tiers never share
+// buckets or cleanup cadence. name namespaces the Store key (alongside
+// client IP), so the same backing store can serve multiple tiers. Every
+// response carries X-RateLimit-Limit/Remaining/Reset; a denied request
+// also gets Retry-After, matching the convention used by GitHub and
+// Mastodon.
+func RateLimitTier(name string, store Store, opts TierOptions) gin.HandlerFunc {
+	rl := NewRateLimiter(store, rate.Every(time.Minute/time.Duration(opts.RequestsPerMinute)), opts.BurstSize)
+
+	if mem, ok := store.(*InMemoryStore); ok {
+		go func() {
+			ticker := time.NewTicker(5 * time.Minute)
+			defer ticker.Stop()
 
-	limiter, exists := rl.limiters[ip]
-	if !exists {
-		limiter = rate.NewLimiter(rl.rate, rl.burst)
-		rl.limiters[ip] = limiter
+			for range ticker.C {
+				mem.sweep()
+			}
+		}()
 	}
 
-	return limiter
+	return func(c *gin.Context) {
+		key := name + ":" + clientKey(c.Request, opts.Key)
+		applyRateLimit(c, rl, key)
+	}
 }
 
-// RateLimit middleware implements rate limiting
-func RateLimit() gin.HandlerFunc {
-	// Create rate limiter: 60 requests per minute with burst of 10
-	rl := NewRateLimiter(rate.Every(time.Minute/60), 10)
+// RateLimit is RateLimitTier for the "default" tier, kept for callers that
+// don't need per-route tiers of their own.
+func RateLimit(store Store, requestsPerMinute, burst int) gin.HandlerFunc {
+	return RateLimitTier("default", store, TierOptions{RequestsPerMinute: requestsPerMinute, BurstSize: burst})
+}
 
-	// Cleanup old limiters every 5 minutes
-	go func() {
-		ticker := time.NewTicker(5 * time.Minute)
-		defer ticker.Stop()
+// applyRateLimit checks key against rl, writes the X-RateLimit-*
+// (and, if denied, Retry-After) headers, and aborts with 429 when the
+// limit is exceeded; shared by RateLimitTier and RateLimitByUser so both
+// report limits the same way.
+func applyRateLimit(c *gin.Context, rl *RateLimiter, key string) {
+	result := rl.Allow(c.Request.Context(), key)
 
-		for range ticker.C {
-			rl.mu.Lock()
-			// Clear all limiters (simple cleanup strategy)
-			rl.limiters = make(map[string]*rate.Limiter)
-			rl.mu.Unlock()
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+	if !result.Allowed {
+		retryAfter := int(math.Ceil(time.Until(result.ResetAt).Seconds()))
+		if retryAfter < 0 {
+			retryAfter = 0
 		}
-	}()
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":   "Rate limit exceeded",
+			"code":    "RATE_LIMIT_EXCEEDED",
+			"message": "Too many requests. Please try again later.",
+		})
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+// PlanTiers maps a billing plan name (e.g. services.PlanFree/PlanPro/
+// PlanEnterprise) to its request quota.
+type PlanTiers map[string]TierOptions
+
+// RateLimitByUser builds a middleware that rate limits by authenticated
+// principal (the "user_id" context key set by AuthRequired) instead of by
+// IP, so a shared-NAT office isn't punished as a single client and a lone
+// abusive token can be throttled precisely. It composes with RateLimitTier:
+// an anonymous request (no "user_id" in context, so this runs before
+// AuthRequired or on an unauthenticated route) is left untouched, falling
+// through to whichever IP-keyed tier already wraps the route. planOf
+// resolves the authenticated principal's plan (typically by looking up
+// their user record); a plan absent from tiers falls back to defaultPlan.
+func RateLimitByUser(store Store, tiers PlanTiers, defaultPlan string, planOf func(c *gin.Context) string) gin.HandlerFunc {
+	limiters := make(map[string]*RateLimiter, len(tiers))
+	for plan, opts := range tiers {
+		limiters[plan] = NewRateLimiter(store, rate.Every(time.Minute/time.Duration(opts.RequestsPerMinute)), opts.BurstSize)
+	}
 
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		limiter := rl.GetLimiter(ip)
-
-		if !limiter.Allow() {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":   "Rate limit exceeded",
-				"code":    "RATE_LIMIT_EXCEEDED",
-				"message": "Too many requests. Please try again later.",
-			})
-			c.Abort()
+		userID, ok := c.Get("user_id")
+		uid, _ := userID.(string)
+		if !ok || uid == "" {
+			c.Next()
 			return
 		}
 
-		c.Next()
+		plan := planOf(c)
+		rl, ok := limiters[plan]
+		if !ok {
+			rl, ok = limiters[defaultPlan]
+		}
+		if !ok {
+			c.Next()
+			return
+		}
+
+		applyRateLimit(c, rl, "user:"+plan+":"+uid)
 	}
-} 
\ No newline at end of file
+}
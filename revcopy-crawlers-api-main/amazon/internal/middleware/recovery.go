@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/revcopy/crawlers/amazon/internal/services"
+)
+
+// Recovery catches panics inside handlers, logs a structured event (stack,
+// method, path, user_id, ASIN if present) to analyticsService, and returns a
+// JSON body in the same shape as every other handler error. Install it in
+// place of gin.Recovery() so panics carry the same X-Request-ID as the rest
+// of the request's logs instead of an opaque 500.
+func Recovery(analyticsService *services.AnalyticsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				requestID := c.GetString("request_id")
+				userID := c.GetString("user_id")
+				stack := string(debug.Stack())
+
+				log.Printf("[PANIC] request_id=%s method=%s path=%s user_id=%s recovered=%v\n%s",
+					requestID, c.Request.Method, c.Request.URL.Path, userID, recovered, stack)
+
+				analyticsService.TrackEvent(userID, "panic_recovered", map[string]interface{}{
+					"request_id": requestID,
+					"method":     c.Request.Method,
+					"path":       c.Request.URL.Path,
+					"asin":       c.Param("asin"),
+					"recovered":  fmt.Sprintf("%v", recovered),
+					"stack":      stack,
+				})
+
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"error":      "Internal server error",
+					"code":       "INTERNAL_PANIC",
+					"request_id": requestID,
+					"message":    "An unexpected error occurred while processing your request",
+				})
+			}
+		}()
+
+		c.Next()
+	}
+}
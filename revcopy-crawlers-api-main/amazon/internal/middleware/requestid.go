@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// requestIDHeader is the header used both to accept a caller-supplied
+// request ID and to echo it back on the response.
+const requestIDHeader = "X-Request-ID"
+
+// RequestID assigns a UUID to every request, honoring one supplied via the
+// X-Request-ID header so a caller's own trace ID is preserved end to end.
+// The ID is echoed on the response header, stored in the context under
+// "request_id" for handlers and Recovery to read, and threaded into
+// ScraperService calls so outbound Amazon fetches can be correlated back to
+// the inbound request that triggered them.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(requestIDHeader, requestID)
+		c.Next()
+	}
+}
@@ -0,0 +1,201 @@
+package oauth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksCacheTTL bounds how long a fetched key set is trusted before the
+// verifier re-fetches it, so a rotated signing key is picked up without a
+// restart.
+const jwksCacheTTL = 15 * time.Minute
+
+// IDTokenVerifier validates an OIDC ID token's signature and standard
+// claims (iss, aud, exp) against a provider's published JSON Web Key Set,
+// and exposes the configured role claim for group-to-role mapping.
+type IDTokenVerifier struct {
+	issuer    string
+	audience  string
+	jwksURL   string
+	roleClaim string
+
+	httpClient *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewIDTokenVerifier builds a verifier for ID tokens issued by issuerURL,
+// fetching signing keys from issuerURL + "/.well-known/jwks.json". roleClaim
+// names the ID token claim holding the caller's groups (e.g. "groups" or
+// "roles"); it defaults to "groups" when empty.
+func NewIDTokenVerifier(issuerURL, audience, roleClaim string) *IDTokenVerifier {
+	if roleClaim == "" {
+		roleClaim = "groups"
+	}
+	return &IDTokenVerifier{
+		issuer:     issuerURL,
+		audience:   audience,
+		jwksURL:    issuerURL + "/.well-known/jwks.json",
+		roleClaim:  roleClaim,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		keys:       make(map[string]*rsa.PublicKey),
+	}
+}
+
+// Verify checks idToken's signature against the provider's JWKS and its iss/
+// aud/exp claims, returning the subject and the configured role claim's
+// values.
+func (v *IDTokenVerifier) Verify(idToken string) (subject string, groups []string, err error) {
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(idToken, claims, v.keyFunc)
+	if err != nil {
+		return "", nil, fmt.Errorf("id token verification failed: %w", err)
+	}
+
+	if iss, _ := claims["iss"].(string); iss != v.issuer {
+		return "", nil, fmt.Errorf("id token issuer %q does not match %q", iss, v.issuer)
+	}
+	if !audienceMatches(claims["aud"], v.audience) {
+		return "", nil, fmt.Errorf("id token audience does not include client %q", v.audience)
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return "", nil, fmt.Errorf("id token has no sub claim")
+	}
+
+	return sub, stringsClaim(claims[v.roleClaim]), nil
+}
+
+// keyFunc resolves the RSA public key matching token's "kid" header,
+// refreshing the cached JWKS on a miss (covers key rotation).
+func (v *IDTokenVerifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	if key, ok := v.cachedKey(kid); ok {
+		return key, nil
+	}
+	if err := v.refreshKeys(); err != nil {
+		return nil, err
+	}
+	if key, ok := v.cachedKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("no signing key found for kid %q", kid)
+}
+
+func (v *IDTokenVerifier) cachedKey(kid string) (*rsa.PublicKey, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if time.Since(v.fetchedAt) > jwksCacheTTL {
+		return nil, false
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+type jwksDocument struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func (v *IDTokenVerifier) refreshKeys() error {
+	resp, err := v.httpClient.Get(v.jwksURL)
+	if err != nil {
+		return fmt.Errorf("fetching jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks endpoint returned status %d", resp.StatusCode)
+	}
+
+	var doc jwksDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("decoding jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		key, err := rsaPublicKeyFromJWK(k.N, k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+
+	return nil
+}
+
+func rsaPublicKeyFromJWK(nEncoded, eEncoded string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(nEncoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(eEncoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding exponent: %w", err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
+
+// audienceMatches reports whether aud (a string or []interface{} per the JWT
+// spec) contains clientID.
+func audienceMatches(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stringsClaim normalizes a claim value that may be a single string or a
+// JSON array of strings into a string slice.
+func stringsClaim(v interface{}) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []interface{}:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
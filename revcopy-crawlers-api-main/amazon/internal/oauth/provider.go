@@ -0,0 +1,72 @@
+// Package oauth implements a minimal OAuth2/OIDC client: pluggable
+// LoginProvider/OAuthProvider implementations so operators can register
+// Google, GitHub, or a generic OIDC issuer as an alternate way to obtain a
+// JWT, alongside this service's own password login.
+package oauth
+
+import (
+	"context"
+	"time"
+)
+
+// UserInfo is the normalized profile returned by an OAuthProvider once the
+// authorization code has been exchanged for a token.
+type UserInfo struct {
+	Subject string // provider-specific stable user ID
+	Email   string
+	Name    string
+	// Groups holds the group/role claim extracted from a verified ID
+	// token, if the provider issues one. Empty for providers (GitHub) that
+	// don't support OIDC ID tokens.
+	Groups []string
+}
+
+// Token is the result of an authorization code exchange.
+type Token struct {
+	AccessToken string
+	TokenType   string
+	ExpiresAt   time.Time
+	// Groups is populated from a verified ID token's role claim, when the
+	// provider was configured with an IDTokenVerifier.
+	Groups []string
+}
+
+// LoginProvider builds the URL a browser is redirected to in order to begin
+// an OAuth2/OIDC login.
+type LoginProvider interface {
+	// Name identifies the provider in routes and registry lookups, e.g. "google".
+	Name() string
+	// AuthCodeURL returns the provider's authorization endpoint URL for the
+	// given opaque state and PKCE code challenge.
+	AuthCodeURL(state, codeChallenge string) string
+}
+
+// OAuthProvider completes an OAuth2/OIDC login: exchanging the authorization
+// code for a token and fetching the authenticated user's profile.
+type OAuthProvider interface {
+	LoginProvider
+	Exchange(ctx context.Context, code, codeVerifier string) (*Token, error)
+	FetchUserInfo(ctx context.Context, token *Token) (*UserInfo, error)
+}
+
+// Registry looks up a configured OAuthProvider by name, e.g. the :provider
+// path parameter in /api/v1/auth/oauth/:provider/login.
+type Registry struct {
+	providers map[string]OAuthProvider
+}
+
+// NewRegistry creates an empty provider registry.
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]OAuthProvider)}
+}
+
+// Register adds p to the registry under its own Name().
+func (r *Registry) Register(p OAuthProvider) {
+	r.providers[p.Name()] = p
+}
+
+// Get returns the provider registered under name, if any.
+func (r *Registry) Get(name string) (OAuthProvider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
@@ -0,0 +1,122 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginState is the data a StateStore associates with an in-flight login's
+// opaque state parameter: which provider started it and the PKCE verifier
+// needed to complete the exchange.
+type LoginState struct {
+	Provider string `json:"provider"`
+	Verifier string `json:"verifier"`
+}
+
+// StateStore holds pending OAuth2/OIDC logins between the redirect to the
+// provider and the callback, keyed by the opaque state value generated by
+// GenerateState. Entries are one-shot: Consume removes them so a replayed
+// callback can't complete the same login twice.
+type StateStore interface {
+	Put(state string, data LoginState, ttl time.Duration) error
+	Consume(state string) (LoginState, bool)
+}
+
+// MemoryStateStore is a process-local StateStore backed by a map, suitable
+// for a single-instance deployment or local development. Expired entries are
+// pruned lazily on access, the same pattern AuthService uses for revoked
+// JWT IDs.
+type MemoryStateStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryStateEntry
+}
+
+type memoryStateEntry struct {
+	data      LoginState
+	expiresAt time.Time
+}
+
+// NewMemoryStateStore creates an empty MemoryStateStore.
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{entries: make(map[string]memoryStateEntry)}
+}
+
+// Put stores data under state until ttl elapses.
+func (s *MemoryStateStore) Put(state string, data LoginState, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[state] = memoryStateEntry{data: data, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Consume returns and removes the entry stored under state, if any and not
+// yet expired.
+func (s *MemoryStateStore) Consume(state string) (LoginState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[state]
+	delete(s.entries, state)
+	if !ok || time.Now().After(entry.expiresAt) {
+		return LoginState{}, false
+	}
+	return entry.data, true
+}
+
+// RedisStateStore is a StateStore backed by Redis, so logins survive a
+// restart and work across multiple service instances behind a load
+// balancer.
+type RedisStateStore struct {
+	client *redis.Client
+}
+
+// NewRedisStateStore connects to redisURL for OAuth login state storage.
+func NewRedisStateStore(redisURL string) (*RedisStateStore, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing redis url: %w", err)
+	}
+	return &RedisStateStore{client: redis.NewClient(opts)}, nil
+}
+
+// Close releases the underlying Redis connection pool.
+func (s *RedisStateStore) Close() error {
+	return s.client.Close()
+}
+
+func stateKey(state string) string {
+	return "oauth_state:" + state
+}
+
+// Put stores data under state until ttl elapses.
+func (s *RedisStateStore) Put(state string, data LoginState, ttl time.Duration) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshaling login state: %w", err)
+	}
+	return s.client.Set(context.Background(), stateKey(state), raw, ttl).Err()
+}
+
+// Consume returns and removes the entry stored under state, if any and not
+// yet expired.
+func (s *RedisStateStore) Consume(state string) (LoginState, bool) {
+	ctx := context.Background()
+	key := stateKey(state)
+
+	raw, err := s.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return LoginState{}, false
+	}
+	s.client.Del(ctx, key)
+
+	var data LoginState
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return LoginState{}, false
+	}
+	return data, true
+}
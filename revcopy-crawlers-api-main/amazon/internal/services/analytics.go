@@ -3,24 +3,76 @@ package services
 import (
 	"sync"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// ringSlots is the number of per-minute buckets kept for sliding-window
+// aggregation (enough to cover the last hour).
+const ringSlots = 60
+
+// topASINsK is the default size of the top-ASINs heavy-hitters heap.
+const topASINsK = 10
+
+// eventRingSize bounds how many recent TrackEvent calls GetEvents can
+// return; once full, each new event overwrites the oldest one.
+const eventRingSize = 1000
+
+// topASINDecayInterval halves the count-min sketch counters on this cadence
+// so ASINs that were popular an hour ago don't crowd out current traffic.
+const topASINDecayInterval = 10 * time.Minute
+
 // AnalyticsService handles analytics and metrics
 type AnalyticsService struct {
-	mu      sync.RWMutex
-	metrics *Metrics
+	mu       sync.RWMutex
+	metrics  *Metrics
+	buckets  [ringSlots]minuteBucket
+	topASINs *topASINTracker
+
+	events     [eventRingSize]Event
+	eventCount int // number of filled slots, capped at eventRingSize
+	eventHead  int // index the next TrackEvent call will write to
+
+	promRequestsTotal     *prometheus.CounterVec
+	promScrapeLatency     *prometheus.HistogramVec
+	promProxyHealthy      *prometheus.GaugeVec
+	promProxyInFlight     *prometheus.GaugeVec
+	promProxyProbeLatency *prometheus.HistogramVec
+	promProxyCaptcha      *prometheus.CounterVec
+	promUserProxyAssign   prometheus.Gauge
+	promCacheHits         prometheus.Counter
+	promCacheMisses       prometheus.Counter
+	promCacheStale        prometheus.Counter
+
+	cacheHits   int64
+	cacheMisses int64
+	cacheStale  int64
+
+	stopDecay chan struct{}
+}
+
+// minuteBucket holds request counts for a single minute slot in the ring.
+// `minute` is the Unix-minute this bucket currently represents; a bucket is
+// reset in place once the ring wraps back around to its slot.
+type minuteBucket struct {
+	minute         int64
+	requests       int64
+	successes      int64
+	failures       int64
+	totalLatencyMs float64
 }
 
 // Metrics holds analytics data
 type Metrics struct {
-	TotalRequests     int64     `json:"total_requests"`
-	SuccessfulScrapes int64     `json:"successful_scrapes"`
-	FailedScrapes     int64     `json:"failed_scrapes"`
-	AverageLatency    float64   `json:"average_latency_ms"`
-	LastScrapeTime    time.Time `json:"last_scrape_time"`
-	StartTime         time.Time `json:"start_time"`
-	ProxyUsage        int64     `json:"proxy_usage_count"`
-	TopASINs          []string  `json:"top_asins"`
+	TotalRequests     int64            `json:"total_requests"`
+	SuccessfulScrapes int64            `json:"successful_scrapes"`
+	FailedScrapes     int64            `json:"failed_scrapes"`
+	AverageLatency    float64          `json:"average_latency_ms"`
+	LastScrapeTime    time.Time        `json:"last_scrape_time"`
+	StartTime         time.Time        `json:"start_time"`
+	ProxyUsage        int64            `json:"proxy_usage_count"`
+	TopASINs          []string         `json:"top_asins"`
 	ErrorCounts       map[string]int64 `json:"error_counts"`
 }
 
@@ -34,15 +86,19 @@ type Event struct {
 
 // PerformanceMetrics holds performance data
 type PerformanceMetrics struct {
-	RequestsPerMinute float64           `json:"requests_per_minute"`
-	SuccessRate       float64           `json:"success_rate"`
-	AverageLatency    float64           `json:"average_latency_ms"`
+	RequestsPerMinute float64            `json:"requests_per_minute"`
+	SuccessRate       float64            `json:"success_rate"`
+	AverageLatency    float64            `json:"average_latency_ms"`
 	ProxyPerformance  map[string]float64 `json:"proxy_performance"`
 	ErrorDistribution map[string]float64 `json:"error_distribution"`
-	LastHourStats     *HourlyStats      `json:"last_hour_stats"`
+	Last1MinStats     *HourlyStats       `json:"last_1m_stats"`
+	Last5MinStats     *HourlyStats       `json:"last_5m_stats"`
+	LastHourStats     *HourlyStats       `json:"last_hour_stats"`
+	CacheHitRatio     float64            `json:"cache_hit_ratio"`
 }
 
-// HourlyStats holds hourly statistics
+// HourlyStats holds statistics for a sliding time window (despite the name,
+// used for the 1m/5m/1h windows alike)
 type HourlyStats struct {
 	Requests  int64   `json:"requests"`
 	Successes int64   `json:"successes"`
@@ -52,24 +108,99 @@ type HourlyStats struct {
 
 // NewAnalyticsService creates a new analytics service
 func NewAnalyticsService() *AnalyticsService {
-	return &AnalyticsService{
+	service := &AnalyticsService{
 		metrics: &Metrics{
-			StartTime:    time.Now(),
-			ErrorCounts:  make(map[string]int64),
-			TopASINs:     make([]string, 0),
+			StartTime:   time.Now(),
+			ErrorCounts: make(map[string]int64),
+			TopASINs:    make([]string, 0),
 		},
+		topASINs:  newTopASINTracker(topASINsK),
+		stopDecay: make(chan struct{}),
+		promRequestsTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "scrape_requests_total",
+			Help: "Total number of scrape requests, labeled by outcome and target domain.",
+		}, []string{"status", "asin_domain"}),
+		promScrapeLatency: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "scrape_latency_seconds",
+			Help:    "Scrape request latency in seconds, labeled by the proxy used.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"proxy_id"}),
+		promProxyHealthy: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "proxy_healthy",
+			Help: "Whether a proxy is currently considered healthy (1) or not (0).",
+		}, []string{"proxy_id"}),
+		promProxyInFlight: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "proxy_in_flight",
+			Help: "Number of in-flight requests currently dispatched through a proxy.",
+		}, []string{"proxy_id"}),
+		promProxyProbeLatency: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "proxy_probe_latency_seconds",
+			Help:    "Background health-check probe latency, labeled by proxy.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"proxy_id"}),
+		promProxyCaptcha: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "proxy_captcha_total",
+			Help: "Number of health-check canary probes that came back CAPTCHA-walled, labeled by proxy.",
+		}, []string{"proxy_id"}),
+		promUserProxyAssign: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "user_proxy_assignments",
+			Help: "Number of users with an explicit proxy assignment.",
+		}),
+		promCacheHits: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "product_cache_hits_total",
+			Help: "Number of GetAmazonProduct requests served from a fresh cache entry.",
+		}),
+		promCacheMisses: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "product_cache_misses_total",
+			Help: "Number of GetAmazonProduct requests that required a live scrape.",
+		}),
+		promCacheStale: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "product_cache_stale_total",
+			Help: "Number of GetAmazonProduct requests served a stale cache entry pending background refresh.",
+		}),
 	}
+
+	go service.runTopASINDecay()
+
+	return service
+}
+
+// runTopASINDecay periodically halves the top-ASINs sketch so heavy hitters
+// from a while ago age out in favor of current traffic.
+func (s *AnalyticsService) runTopASINDecay() {
+	ticker := time.NewTicker(topASINDecayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopDecay:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.topASINs.Decay()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// Stop terminates the background decay goroutine.
+func (s *AnalyticsService) Stop() {
+	close(s.stopDecay)
 }
 
 // TrackRequest tracks a scraping request
-func (s *AnalyticsService) TrackRequest(success bool, latency time.Duration, asin string) {
+func (s *AnalyticsService) TrackRequest(success bool, latency time.Duration, asin, domain, proxyID string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	now := time.Now()
+
 	s.metrics.TotalRequests++
-	s.metrics.LastScrapeTime = time.Now()
+	s.metrics.LastScrapeTime = now
 
+	status := "error"
 	if success {
+		status = "success"
 		s.metrics.SuccessfulScrapes++
 		if asin != "" {
 			s.addToTopASINs(asin)
@@ -82,6 +213,11 @@ func (s *AnalyticsService) TrackRequest(success bool, latency time.Duration, asi
 	currentAvg := s.metrics.AverageLatency
 	totalRequests := float64(s.metrics.TotalRequests)
 	s.metrics.AverageLatency = (currentAvg*(totalRequests-1) + float64(latency.Milliseconds())) / totalRequests
+
+	s.recordBucket(now, success, latency)
+
+	s.promRequestsTotal.WithLabelValues(status, domain).Inc()
+	s.promScrapeLatency.WithLabelValues(proxyID).Observe(latency.Seconds())
 }
 
 // TrackError tracks an error occurrence
@@ -100,10 +236,40 @@ func (s *AnalyticsService) TrackProxyUsage() {
 	s.metrics.ProxyUsage++
 }
 
-// TrackEvent tracks a custom analytics event
+// SetProxyHealthy updates the proxy_healthy gauge for a given proxy.
+func (s *AnalyticsService) SetProxyHealthy(proxyID string, healthy bool) {
+	value := 0.0
+	if healthy {
+		value = 1.0
+	}
+	s.promProxyHealthy.WithLabelValues(proxyID).Set(value)
+}
+
+// SetProxyInFlight updates the proxy_in_flight gauge for a given proxy.
+func (s *AnalyticsService) SetProxyInFlight(proxyID string, count int) {
+	s.promProxyInFlight.WithLabelValues(proxyID).Set(float64(count))
+}
+
+// ObserveProxyProbeLatency records one background health-check probe's
+// round-trip latency for a proxy.
+func (s *AnalyticsService) ObserveProxyProbeLatency(proxyID string, latency time.Duration) {
+	s.promProxyProbeLatency.WithLabelValues(proxyID).Observe(latency.Seconds())
+}
+
+// TrackProxyCaptcha increments the captcha/block counter for a proxy whose
+// health-check canary probe came back CAPTCHA-walled.
+func (s *AnalyticsService) TrackProxyCaptcha(proxyID string) {
+	s.promProxyCaptcha.WithLabelValues(proxyID).Inc()
+}
+
+// SetUserProxyAssignments updates the user_proxy_assignments gauge.
+func (s *AnalyticsService) SetUserProxyAssignments(count int) {
+	s.promUserProxyAssign.Set(float64(count))
+}
+
+// TrackEvent tracks a custom analytics event, retaining it in the ring
+// buffer GetEvents reads from.
 func (s *AnalyticsService) TrackEvent(userID, eventType string, data map[string]interface{}) {
-	// In a real implementation, you might store these events in a database
-	// or send them to an analytics service like Google Analytics or Mixpanel
 	event := Event{
 		Type:      eventType,
 		Timestamp: time.Now(),
@@ -111,8 +277,55 @@ func (s *AnalyticsService) TrackEvent(userID, eventType string, data map[string]
 		Data:      data,
 	}
 
-	// For now, we'll just log the event (in production, persist this)
-	_ = event
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events[s.eventHead] = event
+	s.eventHead = (s.eventHead + 1) % eventRingSize
+	if s.eventCount < eventRingSize {
+		s.eventCount++
+	}
+}
+
+// TrackCacheHit records a GetAmazonProduct request served from a fresh
+// cache entry.
+func (s *AnalyticsService) TrackCacheHit() {
+	s.mu.Lock()
+	s.cacheHits++
+	s.mu.Unlock()
+	s.promCacheHits.Inc()
+}
+
+// TrackCacheMiss records a GetAmazonProduct request that required a live
+// scrape, because nothing was cached or the caller passed ?refresh=true.
+func (s *AnalyticsService) TrackCacheMiss() {
+	s.mu.Lock()
+	s.cacheMisses++
+	s.mu.Unlock()
+	s.promCacheMisses.Inc()
+}
+
+// TrackCacheStale records a GetAmazonProduct request served a stale cache
+// entry (past ?max_age=) while a refresh runs in the background.
+func (s *AnalyticsService) TrackCacheStale() {
+	s.mu.Lock()
+	s.cacheStale++
+	s.mu.Unlock()
+	s.promCacheStale.Inc()
+}
+
+// GetEvents returns up to eventRingSize most recently tracked events,
+// newest first.
+func (s *AnalyticsService) GetEvents() []Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := make([]Event, s.eventCount)
+	for i := 0; i < s.eventCount; i++ {
+		idx := (s.eventHead - 1 - i + eventRingSize) % eventRingSize
+		events[i] = s.events[idx]
+	}
+	return events
 }
 
 // GetStats returns current analytics statistics
@@ -140,7 +353,7 @@ func (s *AnalyticsService) GetPerformanceMetrics() *PerformanceMetrics {
 
 	uptime := time.Since(s.metrics.StartTime)
 	requestsPerMinute := float64(s.metrics.TotalRequests) / uptime.Minutes()
-	
+
 	var successRate float64
 	if s.metrics.TotalRequests > 0 {
 		successRate = float64(s.metrics.SuccessfulScrapes) / float64(s.metrics.TotalRequests) * 100
@@ -155,20 +368,25 @@ func (s *AnalyticsService) GetPerformanceMetrics() *PerformanceMetrics {
 		}
 	}
 
+	now := time.Now()
+
+	var cacheHitRatio float64
+	if totalCacheLookups := s.cacheHits + s.cacheMisses + s.cacheStale; totalCacheLookups > 0 {
+		cacheHitRatio = float64(s.cacheHits+s.cacheStale) / float64(totalCacheLookups) * 100
+	}
+
 	return &PerformanceMetrics{
 		RequestsPerMinute: requestsPerMinute,
 		SuccessRate:       successRate,
 		AverageLatency:    s.metrics.AverageLatency,
-		ProxyPerformance:  map[string]float64{
+		ProxyPerformance: map[string]float64{
 			"usage_percentage": float64(s.metrics.ProxyUsage) / float64(s.metrics.TotalRequests) * 100,
 		},
 		ErrorDistribution: errorDistribution,
-		LastHourStats: &HourlyStats{
-			Requests:  s.metrics.TotalRequests, // Simplified for this example
-			Successes: s.metrics.SuccessfulScrapes,
-			Failures:  s.metrics.FailedScrapes,
-			AvgTime:   s.metrics.AverageLatency,
-		},
+		Last1MinStats:     s.windowStats(now, 1),
+		Last5MinStats:     s.windowStats(now, 5),
+		LastHourStats:     s.windowStats(now, 60),
+		CacheHitRatio:     cacheHitRatio,
 	}
 }
 
@@ -182,24 +400,82 @@ func (s *AnalyticsService) ResetStats() {
 		ErrorCounts: make(map[string]int64),
 		TopASINs:    make([]string, 0),
 	}
+	s.buckets = [ringSlots]minuteBucket{}
+	s.topASINs = newTopASINTracker(topASINsK)
+	s.events = [eventRingSize]Event{}
+	s.eventCount = 0
+	s.eventHead = 0
+	s.cacheHits = 0
+	s.cacheMisses = 0
+	s.cacheStale = 0
 }
 
-// addToTopASINs adds an ASIN to the top ASINs list (simplified implementation)
-func (s *AnalyticsService) addToTopASINs(asin string) {
-	// Simple implementation - just keep the last 10 unique ASINs
-	for i, existingASIN := range s.metrics.TopASINs {
-		if existingASIN == asin {
-			// Move to front
-			s.metrics.TopASINs = append([]string{asin}, append(s.metrics.TopASINs[:i], s.metrics.TopASINs[i+1:]...)...)
-			return
+// recordBucket rolls the request into the per-minute ring slot for `at`.
+// Callers must hold s.mu.
+func (s *AnalyticsService) recordBucket(at time.Time, success bool, latency time.Duration) {
+	minute := at.Unix() / 60
+	slot := &s.buckets[minute%ringSlots]
+
+	if slot.minute != minute {
+		*slot = minuteBucket{minute: minute}
+	}
+
+	slot.requests++
+	if success {
+		slot.successes++
+	} else {
+		slot.failures++
+	}
+	slot.totalLatencyMs += float64(latency.Milliseconds())
+}
+
+// windowStats aggregates the ring buckets covering the last `windowMinutes`
+// minutes up to and including the bucket for `now`. Callers must hold
+// s.mu (read or write).
+func (s *AnalyticsService) windowStats(now time.Time, windowMinutes int) *HourlyStats {
+	currentMinute := now.Unix() / 60
+	stats := &HourlyStats{}
+	var totalLatencyMs float64
+
+	for i := 0; i < windowMinutes && i < ringSlots; i++ {
+		minute := currentMinute - int64(i)
+		slot := &s.buckets[minute%ringSlots]
+		if slot.minute != minute {
+			continue // slot has been overwritten by a more recent minute, or never used
 		}
+
+		stats.Requests += slot.requests
+		stats.Successes += slot.successes
+		stats.Failures += slot.failures
+		totalLatencyMs += slot.totalLatencyMs
+	}
+
+	if stats.Requests > 0 {
+		stats.AvgTime = totalLatencyMs / float64(stats.Requests)
 	}
 
-	// Add new ASIN to front
-	s.metrics.TopASINs = append([]string{asin}, s.metrics.TopASINs...)
-	
-	// Keep only top 10
-	if len(s.metrics.TopASINs) > 10 {
-		s.metrics.TopASINs = s.metrics.TopASINs[:10]
+	return stats
+}
+
+// addToTopASINs feeds asin into the heavy-hitters tracker and refreshes the
+// plain-string TopASINs field kept on Metrics for backward compatibility.
+// Callers must hold s.mu.
+func (s *AnalyticsService) addToTopASINs(asin string) {
+	s.topASINs.Record(asin)
+
+	top := s.topASINs.Top(topASINsK)
+	asins := make([]string, len(top))
+	for i, entry := range top {
+		asins[i] = entry.ASIN
 	}
-} 
\ No newline at end of file
+	s.metrics.TopASINs = asins
+}
+
+// GetTopASINs returns the k ASINs with the highest estimated request count,
+// sorted descending, as tracked by the count-min sketch heavy-hitters algorithm.
+func (s *AnalyticsService) GetTopASINs(k int) []ASINCount {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.topASINs.Top(k)
+}
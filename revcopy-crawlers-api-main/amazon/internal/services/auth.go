@@ -2,22 +2,54 @@ package services
 
 import (
 	"errors"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
+// Authentication types a User can carry. Password-authenticated users log
+// in via Login; oauth-authenticated users can only obtain a JWT through the
+// OAuth2/OIDC flow, since they never set a password.
+const (
+	AuthTypePassword = "password"
+	AuthTypeOAuth    = "oauth"
+)
+
+// Billing plans a User can be on, used by
+// internal/middleware.RateLimitByUser to size that user's request quota.
+// A user with no plan set behaves as PlanFree.
+const (
+	PlanFree       = "free"
+	PlanPro        = "pro"
+	PlanEnterprise = "enterprise"
+)
+
 // AuthService handles authentication operations
 type AuthService struct {
-	jwtSecret []byte
-	users     map[string]*User // In-memory user storage (use database in production)
+	jwtSecret  []byte
+	bcryptCost int
+	repo       UserRepository // User storage; in-memory by default, Postgres in production
+	tokens     TokenStore     // Personal access token storage
+
+	rolesMu sync.RWMutex
+	roles   map[string]*Role // In-memory role storage (use database in production)
+
+	oauthRoleMapping map[string]string // SSO group -> local role, set via SetOAuthRoleMapping
+
+	revokedMu  sync.RWMutex
+	revokedJTI map[string]time.Time // jti -> original token expiry, so entries can be pruned once they'd have expired anyway
 }
 
 // Claims represents JWT claims
 type Claims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
+	UserID      string   `json:"user_id"`
+	Username    string   `json:"username"`
+	Role        string   `json:"role"`
+	Plan        string   `json:"plan"`
+	Permissions []string `json:"permissions"`
 	jwt.RegisteredClaims
 }
 
@@ -39,11 +71,26 @@ type LoginResponse struct {
 type User struct {
 	ID        string    `json:"id"`
 	Username  string    `json:"username"`
-	Password  string    `json:"-"` // Never expose password in JSON
+	Password  string    `json:"-"` // bcrypt hash; never expose in JSON
 	Role      string    `json:"role"`
 	Active    bool      `json:"active"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Plan is this user's billing plan (PlanFree, PlanPro,
+	// PlanEnterprise), sizing their request quota under
+	// internal/middleware.RateLimitByUser. Empty behaves as PlanFree.
+	Plan string `json:"plan"`
+
+	// AuthType discriminates how this user logs in: AuthTypePassword (the
+	// default) accepts Login's username/password, AuthTypeOAuth was
+	// provisioned by an SSO callback and has no password to check.
+	AuthType string `json:"auth_type"`
+
+	// OAuthSubject is the provider-specific stable subject ID for an
+	// AuthTypeOAuth user, used to find their account again even if their
+	// email address changes. Empty for password users.
+	OAuthSubject string `json:"-"`
 }
 
 // CreateUserRequest represents user creation request
@@ -52,6 +99,8 @@ type CreateUserRequest struct {
 	Password string `json:"password" binding:"required"`
 	Role     string `json:"role" binding:"required"`
 	Active   bool   `json:"active"`
+	// Plan defaults to PlanFree when empty.
+	Plan string `json:"plan,omitempty"`
 }
 
 // UpdateUserRequest represents user update request
@@ -59,6 +108,7 @@ type UpdateUserRequest struct {
 	Password string `json:"password,omitempty"`
 	Role     string `json:"role,omitempty"`
 	Active   *bool  `json:"active,omitempty"`
+	Plan     string `json:"plan,omitempty"`
 }
 
 // UserResponse represents user response (without sensitive data)
@@ -66,79 +116,133 @@ type UserResponse struct {
 	ID        string    `json:"id"`
 	Username  string    `json:"username"`
 	Role      string    `json:"role"`
+	Plan      string    `json:"plan"`
 	Active    bool      `json:"active"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	AuthType  string    `json:"auth_type"`
 }
 
-// NewAuthService creates a new auth service
-func NewAuthService(jwtSecret string) *AuthService {
+// NewAuthService creates a new auth service backed by repo. Pass a
+// PostgresUserRepository in production so accounts survive restarts, or
+// NewInMemoryUserRepository() for local dev and tests.
+func NewAuthService(jwtSecret string, tokenStore TokenStore, repo UserRepository, bcryptCost int) *AuthService {
 	service := &AuthService{
-		jwtSecret: []byte(jwtSecret),
-		users:     make(map[string]*User),
+		jwtSecret:  []byte(jwtSecret),
+		bcryptCost: bcryptCost,
+		repo:       repo,
+		roles:      defaultRoles(),
+		tokens:     tokenStore,
+		revokedJTI: make(map[string]time.Time),
 	}
-	
-	// Create default users
+
 	service.createDefaultUsers()
-	
+	service.migrateLegacyPasswords()
+
 	return service
 }
 
-// createDefaultUsers creates the default system users
+// SetOAuthRoleMapping configures the SSO group -> local role table consulted
+// by LoginOAuth when provisioning a new user. Groups with no entry fall back
+// to the "user" role.
+func (s *AuthService) SetOAuthRoleMapping(mapping map[string]string) {
+	s.oauthRoleMapping = mapping
+}
+
+// roleForOAuthGroups returns the local role for the first of groups that
+// appears in oauthRoleMapping, or "user" if none match.
+func (s *AuthService) roleForOAuthGroups(groups []string) string {
+	for _, group := range groups {
+		if role, ok := s.oauthRoleMapping[group]; ok {
+			return role
+		}
+	}
+	return "user"
+}
+
+// createDefaultUsers seeds the default system users, skipping any that
+// already exist in repo (e.g. a Postgres backend that already ran this seed
+// on a prior boot).
 func (s *AuthService) createDefaultUsers() {
 	defaultUsers := []User{
-		{
-			ID:        uuid.New().String(),
-			Username:  "admin",
-			Password:  "admin123",
-			Role:      "admin",
-			Active:    true,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
-		{
-			ID:        uuid.New().String(),
-			Username:  "crawler",
-			Password:  "crawler123",
-			Role:      "crawler",
-			Active:    true,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
-		{
-			ID:        uuid.New().String(),
-			Username:  "analytics",
-			Password:  "analytics123",
-			Role:      "analytics",
-			Active:    true,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
+		{Username: "admin", Password: "admin123", Role: "admin", Plan: PlanEnterprise},
+		{Username: "crawler", Password: "crawler123", Role: "crawler", Plan: PlanPro},
+		{Username: "analytics", Password: "analytics123", Role: "analytics", Plan: PlanPro},
+	}
+
+	for i := range defaultUsers {
+		user := defaultUsers[i]
+
+		if _, err := s.repo.Get(user.Username); err == nil {
+			continue
+		}
+
+		hashed, err := hashPassword(user.Password, s.bcryptCost)
+		if err != nil {
+			log.Printf("failed to hash default password for %s: %v", user.Username, err)
+			continue
+		}
+
+		user.ID = uuid.New().String()
+		user.Password = hashed
+		user.Active = true
+		user.AuthType = AuthTypePassword
+		user.CreatedAt = time.Now()
+		user.UpdatedAt = time.Now()
+
+		if err := s.repo.Create(&user); err != nil {
+			log.Printf("failed to create default user %s: %v", user.Username, err)
+		}
+	}
+}
+
+// migrateLegacyPasswords rehashes any persisted user whose password is not
+// already a bcrypt hash (e.g. seeded by an older build of this service that
+// stored plaintext), so every password at rest ends up behind bcrypt.
+func (s *AuthService) migrateLegacyPasswords() {
+	users, err := s.repo.List()
+	if err != nil {
+		log.Printf("failed to list users for password migration: %v", err)
+		return
 	}
-	
-	for _, user := range defaultUsers {
-		s.users[user.Username] = &user
+
+	for _, user := range users {
+		if user.AuthType == AuthTypeOAuth || isBcryptHash(user.Password) {
+			continue
+		}
+
+		hashed, err := hashPassword(user.Password, s.bcryptCost)
+		if err != nil {
+			log.Printf("failed to rehash password for %s: %v", user.Username, err)
+			continue
+		}
+
+		user.Password = hashed
+		if err := s.repo.Update(user); err != nil {
+			log.Printf("failed to persist rehashed password for %s: %v", user.Username, err)
+		}
 	}
 }
 
 // Login authenticates user and returns JWT tokens
 func (s *AuthService) Login(username, password string) (*LoginResponse, error) {
-	// In a real implementation, you would validate credentials against a database
-	// For this example, we'll use a simple hardcoded check
 	if !s.validateCredentials(username, password) {
 		return nil, errors.New("invalid credentials")
 	}
 
-	userID := uuid.New().String()
-	
+	user, err := s.repo.Get(username)
+	if err != nil {
+		return nil, errors.New("invalid credentials")
+	}
+
 	// Generate access token (1 hour expiry)
-	accessToken, err := s.generateToken(userID, username, time.Hour)
+	accessToken, err := s.generateToken(user.ID, user.Username, user.Role, user.Plan, time.Hour)
 	if err != nil {
 		return nil, err
 	}
 
 	// Generate refresh token (7 days expiry)
-	refreshToken, err := s.generateToken(userID, username, 7*24*time.Hour)
+	refreshToken, err := s.generateToken(user.ID, user.Username, user.Role, user.Plan, 7*24*time.Hour)
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +255,68 @@ func (s *AuthService) Login(username, password string) (*LoginResponse, error) {
 	}, nil
 }
 
+// LoginOAuth upserts a User for an SSO-authenticated profile, looked up by
+// its stable provider subject ID so the account survives the user's email
+// changing. It fails if email is already registered for password login,
+// since that account has a password this flow can't check. groups is the
+// caller's SSO group membership (from a verified ID token, if the provider
+// issues one); it selects the role a newly provisioned user is created
+// with, via oauthRoleMapping.
+func (s *AuthService) LoginOAuth(subject, email string, groups []string) (*LoginResponse, error) {
+	if email == "" {
+		return nil, errors.New("oauth profile has no email")
+	}
+
+	user, err := s.repo.FindByOAuthSubject(subject)
+	if err != nil {
+		user, err = s.repo.Get(email)
+		if err == nil {
+			if user.AuthType != AuthTypeOAuth {
+				return nil, errors.New("account already registered for password login")
+			}
+		} else {
+			user = &User{
+				ID:           uuid.New().String(),
+				Username:     email,
+				Role:         s.roleForOAuthGroups(groups),
+				Plan:         PlanFree,
+				Active:       true,
+				CreatedAt:    time.Now(),
+				AuthType:     AuthTypeOAuth,
+				OAuthSubject: subject,
+			}
+			if err := s.repo.Create(user); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if !user.Active {
+		return nil, errors.New("user account is disabled")
+	}
+	user.UpdatedAt = time.Now()
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.generateToken(user.ID, user.Username, user.Role, user.Plan, time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := s.generateToken(user.ID, user.Username, user.Role, user.Plan, 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    3600,
+		TokenType:    "Bearer",
+	}, nil
+}
+
 // ValidateToken validates a JWT token and returns claims
 func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
@@ -162,12 +328,49 @@ func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		if s.isRevoked(claims.ID) {
+			return nil, errors.New("token has been revoked")
+		}
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
 
+// Logout revokes the JWT identified by jti, rejecting it from ValidateToken
+// for the remainder of its natural lifetime (expiresAt).
+func (s *AuthService) Logout(jti string, expiresAt time.Time) error {
+	if jti == "" {
+		return errors.New("token has no jti claim to revoke")
+	}
+
+	s.revokedMu.Lock()
+	defer s.revokedMu.Unlock()
+	s.revokedJTI[jti] = expiresAt
+	s.pruneRevoked()
+	return nil
+}
+
+// isRevoked reports whether jti has been logged out.
+func (s *AuthService) isRevoked(jti string) bool {
+	s.revokedMu.RLock()
+	defer s.revokedMu.RUnlock()
+	_, revoked := s.revokedJTI[jti]
+	return revoked
+}
+
+// pruneRevoked drops blacklist entries whose underlying token has already
+// expired on its own, since they can never be presented again. Callers must
+// hold revokedMu for writing.
+func (s *AuthService) pruneRevoked() {
+	now := time.Now()
+	for jti, expiresAt := range s.revokedJTI {
+		if now.After(expiresAt) {
+			delete(s.revokedJTI, jti)
+		}
+	}
+}
+
 // RefreshToken generates a new access token from a refresh token
 func (s *AuthService) RefreshToken(refreshTokenString string) (*LoginResponse, error) {
 	claims, err := s.ValidateToken(refreshTokenString)
@@ -176,7 +379,7 @@ func (s *AuthService) RefreshToken(refreshTokenString string) (*LoginResponse, e
 	}
 
 	// Generate new access token
-	accessToken, err := s.generateToken(claims.UserID, claims.Username, time.Hour)
+	accessToken, err := s.generateToken(claims.UserID, claims.Username, claims.Role, claims.Plan, time.Hour)
 	if err != nil {
 		return nil, err
 	}
@@ -188,12 +391,18 @@ func (s *AuthService) RefreshToken(refreshTokenString string) (*LoginResponse, e
 	}, nil
 }
 
-// generateToken generates a JWT token
-func (s *AuthService) generateToken(userID, username string, expiry time.Duration) (string, error) {
+// generateToken generates a JWT token, embedding the role and its current
+// permission set so middleware.RequirePermission can check access without a
+// second lookup against the role store.
+func (s *AuthService) generateToken(userID, username, role, plan string, expiry time.Duration) (string, error) {
 	claims := Claims{
-		UserID:   userID,
-		Username: username,
+		UserID:      userID,
+		Username:    username,
+		Role:        role,
+		Plan:        plan,
+		Permissions: s.permissionsFor(role),
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -207,144 +416,144 @@ func (s *AuthService) generateToken(userID, username string, expiry time.Duratio
 
 // validateCredentials validates user credentials
 func (s *AuthService) validateCredentials(username, password string) bool {
-	user, exists := s.users[username]
-	if !exists || !user.Active {
+	user, err := s.repo.Get(username)
+	if err != nil || !user.Active {
+		return false
+	}
+	if user.AuthType == AuthTypeOAuth {
+		// OAuth-provisioned users have no password to check.
 		return false
 	}
-	
-	return user.Password == password
+
+	return checkPassword(user.Password, password)
 }
 
 // CreateUser creates a new user
 func (s *AuthService) CreateUser(req CreateUserRequest) (*UserResponse, error) {
-	// Check if user already exists
-	if _, exists := s.users[req.Username]; exists {
+	if _, err := s.repo.Get(req.Username); err == nil {
 		return nil, errors.New("user already exists")
 	}
-	
-	// Validate role
-	validRoles := map[string]bool{
-		"admin":     true,
-		"crawler":   true,
-		"analytics": true,
-		"user":      true,
-	}
-	
-	if !validRoles[req.Role] {
+
+	if _, exists := s.roles[req.Role]; !exists {
 		return nil, errors.New("invalid role")
 	}
-	
-	// Create new user
+
+	hashed, err := hashPassword(req.Password, s.bcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := req.Plan
+	if plan == "" {
+		plan = PlanFree
+	}
+
 	user := &User{
 		ID:        uuid.New().String(),
 		Username:  req.Username,
-		Password:  req.Password, // In production, hash this password
+		Password:  hashed,
 		Role:      req.Role,
+		Plan:      plan,
 		Active:    req.Active,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+		AuthType:  AuthTypePassword,
 	}
-	
-	s.users[req.Username] = user
-	
-	return &UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Role:      user.Role,
-		Active:    user.Active,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}, nil
+
+	if err := s.repo.Create(user); err != nil {
+		return nil, err
+	}
+
+	return toUserResponse(user), nil
 }
 
 // GetUser gets a user by username
 func (s *AuthService) GetUser(username string) (*UserResponse, error) {
-	user, exists := s.users[username]
-	if !exists {
+	user, err := s.repo.Get(username)
+	if err != nil {
 		return nil, errors.New("user not found")
 	}
-	
-	return &UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Role:      user.Role,
-		Active:    user.Active,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}, nil
+
+	return toUserResponse(user), nil
 }
 
 // ListUsers lists all users
 func (s *AuthService) ListUsers() []*UserResponse {
-	var users []*UserResponse
-	
-	for _, user := range s.users {
-		users = append(users, &UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Role:      user.Role,
-			Active:    user.Active,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		})
-	}
-	
-	return users
+	users, err := s.repo.List()
+	if err != nil {
+		return nil
+	}
+
+	responses := make([]*UserResponse, 0, len(users))
+	for _, user := range users {
+		responses = append(responses, toUserResponse(user))
+	}
+	return responses
 }
 
 // UpdateUser updates an existing user
 func (s *AuthService) UpdateUser(username string, req UpdateUserRequest) (*UserResponse, error) {
-	user, exists := s.users[username]
-	if !exists {
+	user, err := s.repo.Get(username)
+	if err != nil {
 		return nil, errors.New("user not found")
 	}
-	
-	// Update fields if provided
+
 	if req.Password != "" {
-		user.Password = req.Password // In production, hash this password
+		hashed, err := hashPassword(req.Password, s.bcryptCost)
+		if err != nil {
+			return nil, err
+		}
+		user.Password = hashed
 	}
-	
+
 	if req.Role != "" {
-		validRoles := map[string]bool{
-			"admin":     true,
-			"crawler":   true,
-			"analytics": true,
-			"user":      true,
-		}
-		
-		if !validRoles[req.Role] {
+		if _, exists := s.roles[req.Role]; !exists {
 			return nil, errors.New("invalid role")
 		}
 		user.Role = req.Role
 	}
-	
+
 	if req.Active != nil {
 		user.Active = *req.Active
 	}
-	
+
+	if req.Plan != "" {
+		user.Plan = req.Plan
+	}
+
 	user.UpdatedAt = time.Now()
-	
-	return &UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Role:      user.Role,
-		Active:    user.Active,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}, nil
+
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return toUserResponse(user), nil
 }
 
 // DeleteUser deletes a user
 func (s *AuthService) DeleteUser(username string) error {
-	if _, exists := s.users[username]; !exists {
-		return errors.New("user not found")
-	}
-	
 	// Don't allow deleting admin user
 	if username == "admin" {
 		return errors.New("cannot delete admin user")
 	}
-	
-	delete(s.users, username)
-	return nil
-} 
\ No newline at end of file
+
+	if _, err := s.repo.Get(username); err != nil {
+		return errors.New("user not found")
+	}
+
+	return s.repo.Delete(username)
+}
+
+// toUserResponse strips sensitive fields from user for API responses.
+func toUserResponse(user *User) *UserResponse {
+	return &UserResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		Plan:      user.Plan,
+		Active:    user.Active,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		AuthType:  user.AuthType,
+	}
+}
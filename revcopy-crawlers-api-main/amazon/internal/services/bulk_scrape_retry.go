@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/revcopy/crawlers/amazon/internal/clients"
+	"github.com/revcopy/crawlers/amazon/internal/config"
+)
+
+// maxScrapeAttempts bounds how many times BulkScrapeProducts retries a
+// single URL after a retryable (5xx/429) failure.
+const maxScrapeAttempts = 3
+
+// bulkScrapeOutcome is one URL's result from a BulkScrapeProducts worker:
+// exactly one of product or failed is set.
+type bulkScrapeOutcome struct {
+	product *AmazonProduct
+	failed  *FailedURL
+}
+
+// hostLimiter hands out a token-bucket rate.Limiter per hostname, built
+// lazily from cfg on first use, so amazon.com and amazon.es (say) are
+// throttled independently within the same BulkScrapeProducts call.
+type hostLimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	cfg      config.RateLimitConfig
+}
+
+// newHostLimiter creates a hostLimiter using cfg for every host's bucket.
+func newHostLimiter(cfg config.RateLimitConfig) *hostLimiter {
+	return &hostLimiter{limiters: make(map[string]*rate.Limiter), cfg: cfg}
+}
+
+// wait blocks until host's bucket has a token, creating the bucket on first
+// use.
+func (h *hostLimiter) wait(ctx context.Context, host string) error {
+	h.mu.Lock()
+	limiter, ok := h.limiters[host]
+	if !ok {
+		rps := float64(h.cfg.RequestsPerMinute) / 60
+		if rps <= 0 {
+			rps = 1
+		}
+		burst := h.cfg.BurstSize
+		if burst <= 0 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(rps), burst)
+		h.limiters[host] = limiter
+	}
+	h.mu.Unlock()
+
+	return limiter.Wait(ctx)
+}
+
+// scrapeWithRetry scrapes rawURL, retrying with exponential backoff while
+// the failure is a retryable 5xx/429 and attempts remain.
+func (s *ScraperService) scrapeWithRetry(requestID, rawURL string, limiter *hostLimiter) bulkScrapeOutcome {
+	host := ""
+	if u, err := url.Parse(rawURL); err == nil {
+		host = u.Hostname()
+	}
+
+	var lastErr error
+	var statusCode int
+	attempts := 0
+
+	for attempts < maxScrapeAttempts {
+		attempts++
+
+		if host != "" {
+			if err := limiter.wait(context.Background(), host); err != nil {
+				lastErr = err
+				break
+			}
+		}
+
+		product, err := s.ScrapeProduct(requestID, rawURL)
+		if err == nil {
+			return bulkScrapeOutcome{product: product}
+		}
+
+		lastErr = err
+		statusCode = statusCodeOf(err)
+
+		if !isRetryableStatus(statusCode) || attempts == maxScrapeAttempts {
+			break
+		}
+
+		time.Sleep(retryBackoff(attempts))
+	}
+
+	return bulkScrapeOutcome{failed: &FailedURL{
+		URL:        rawURL,
+		Err:        lastErr.Error(),
+		StatusCode: statusCode,
+		Attempts:   attempts,
+	}}
+}
+
+// statusCodeOf extracts the upstream HTTP status code from err, if any.
+func statusCodeOf(err error) int {
+	var statusErr *clients.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	return 0
+}
+
+// isRetryableStatus reports whether code is worth retrying: a missing code
+// (a transport-level error, not an HTTP response) or a 5xx/429.
+func isRetryableStatus(code int) bool {
+	return code == 0 || code >= 500 || code == 429
+}
+
+// retryBackoff returns the delay before retry number attempt+1: 500ms,
+// 1s, 2s, doubling each time.
+func retryBackoff(attempt int) time.Duration {
+	return 500 * time.Millisecond * time.Duration(1<<uint(attempt-1))
+}
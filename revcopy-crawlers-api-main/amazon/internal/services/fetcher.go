@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/revcopy/crawlers/amazon/internal/clients"
+)
+
+// FetchOptions customizes a Fetch call. RequireSelector, if set, is a CSS
+// selector that must appear in the returned HTML; a RenderingFetcher treats
+// its absence as a signal the plain HTTP response needs a headless retry.
+type FetchOptions struct {
+	RequireSelector string
+}
+
+// Fetcher retrieves a page's HTML for a URL, with no opinion on what's then
+// done with it. HTTPFetcher is a plain GET; RenderingFetcher wraps it with a
+// headless-Chrome fallback for pages that need JS execution or trip a
+// bot-wall heuristic.
+type Fetcher interface {
+	Fetch(requestID, rawURL string, opts FetchOptions) (string, error)
+}
+
+// HTTPFetcher is the default Fetcher: a single GET through a clients.Client,
+// with scraper headers set. newClient is a func rather than a fixed Client
+// so each Fetch can build one carrying that call's X-Request-ID header and,
+// for a proxy-pool-backed client, pick a proxy sticky to the URL's product.
+type HTTPFetcher struct {
+	newClient func(requestID, rawURL string) clients.Client
+}
+
+// NewHTTPFetcher creates the default plain-HTTP Fetcher, fetching through a
+// proxy sticky to rawURL's product (see ProxyService.GetClientForKey) so
+// retries of the same product reuse the same egress IP.
+func NewHTTPFetcher(proxyService *ProxyService) *HTTPFetcher {
+	return &HTTPFetcher{
+		newClient: func(requestID, rawURL string) clients.Client {
+			var targetHost string
+			if u, err := url.Parse(rawURL); err == nil {
+				targetHost = u.Hostname()
+			}
+			client := proxyService.GetClientForKey(stickyKeyFromURL(rawURL), targetHost)
+			return clients.NewHTTPClient(client, scraperHeaders(requestID))
+		},
+	}
+}
+
+// NewHTTPFetcherWithClient builds an HTTPFetcher around a fixed
+// clients.Client, ignoring requestID/rawURL. Used to swap in a MockClient or
+// RecordReplayClient so ScraperService's parsing logic can run against
+// fixture HTML instead of the network.
+func NewHTTPFetcherWithClient(client clients.Client) *HTTPFetcher {
+	return &HTTPFetcher{
+		newClient: func(requestID, rawURL string) clients.Client { return client },
+	}
+}
+
+// Fetch issues a single GET for rawURL and returns the response body.
+func (f *HTTPFetcher) Fetch(requestID, rawURL string, opts FetchOptions) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+
+	body, err := f.newClient(requestID, rawURL).Get(u)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch page: %w", err)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// RenderingFetcher retries through a headless-Chrome RendererClient when the
+// plain Fetch comes back empty, shows a CAPTCHA wall, or is missing
+// opts.RequireSelector.
+type RenderingFetcher struct {
+	plain    Fetcher
+	renderer *RendererClient
+}
+
+// NewRenderingFetcher wraps plain with renderer, only falling back to it
+// when plain's response trips looksBlocked. renderer's own Enabled setting
+// governs whether it ever actually runs.
+func NewRenderingFetcher(plain Fetcher, renderer *RendererClient) *RenderingFetcher {
+	return &RenderingFetcher{plain: plain, renderer: renderer}
+}
+
+// Fetch tries plain first and only pays for a headless render when the
+// result looks blocked or incomplete.
+func (f *RenderingFetcher) Fetch(requestID, rawURL string, opts FetchOptions) (string, error) {
+	html, err := f.plain.Fetch(requestID, rawURL, opts)
+	if err == nil && !looksBlocked(html, opts) {
+		return html, nil
+	}
+
+	if !f.renderer.enabled() {
+		if err != nil {
+			return "", err
+		}
+		return html, nil
+	}
+
+	rendered, renderErr := f.renderer.Render(rawURL, opts)
+	if renderErr != nil {
+		if err != nil {
+			return "", fmt.Errorf("plain fetch failed (%v) and headless render failed: %w", err, renderErr)
+		}
+		// The plain fetch at least returned something even though it
+		// tripped the blocked heuristic; better than nothing.
+		return html, nil
+	}
+
+	return rendered, nil
+}
+
+// looksBlocked reports whether html looks like a bot wall or an incomplete
+// render rather than real content.
+func looksBlocked(html string, opts FetchOptions) bool {
+	if html == "" {
+		return true
+	}
+	if strings.Contains(html, "Enter the characters you see below") {
+		return true
+	}
+	if opts.RequireSelector == "" {
+		return false
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return true
+	}
+	return doc.Find(opts.RequireSelector).Length() == 0
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// Marketplace is the unit of dispatch for the /api/v1/marketplaces/{name}/*
+// endpoints: a named scraping backend, either the built-in Amazon stack
+// (amazonMarketplace, wrapping ScraperService/ShopManager) or a plugin
+// loaded at startup from a plugin.yaml manifest (see marketplace_plugin.go).
+// Handlers look one up by name instead of hardcoding which backend serves a
+// request, the same way ShopManager looks one up by hostname.
+type Marketplace interface {
+	// Name is the identifier this Marketplace is registered and dispatched
+	// under, e.g. "amazon" or "walmart".
+	Name() string
+
+	// IdentifierFromURL extracts this marketplace's product identifier (an
+	// ASIN, a SKU, ...) from a product URL, so a caller that only has the
+	// identifier can rebuild a scrape target.
+	IdentifierFromURL(rawURL string) (string, error)
+
+	ScrapeProduct(requestID, rawURL string, opts ...ScrapeOption) (*AmazonProduct, error)
+	SearchProducts(requestID, query string, opts SearchOptions) ([]*AmazonProduct, error)
+	BulkScrapeProducts(requestID string, urls []string) (*BulkResult, error)
+}
+
+// MarketplaceManifest describes a registered Marketplace for discovery via
+// GET /api/v1/marketplaces: what it can do, how hard it can be hit, and
+// which proxy regions it needs a healthy proxy from (see
+// ProxyService.GetClientForMarketplace).
+type MarketplaceManifest struct {
+	Name                 string   `json:"name" yaml:"name"`
+	Capabilities         []string `json:"capabilities,omitempty" yaml:"capabilities"`
+	RateLimitPerMinute   int      `json:"rate_limit_per_minute,omitempty" yaml:"rate_limit_per_minute"`
+	RequiredProxyRegions []string `json:"required_proxy_regions,omitempty" yaml:"required_proxy_regions"`
+	// Builtin is true for the in-process Amazon marketplace, false for
+	// anything loaded from a plugin manifest.
+	Builtin bool `json:"builtin"`
+}
+
+// MarketplaceRegistry dispatches by name to a Marketplace, analogous to how
+// ShopManager dispatches by hostname to a Shop.
+type MarketplaceRegistry struct {
+	mu        sync.RWMutex
+	entries   map[string]Marketplace
+	manifests map[string]MarketplaceManifest
+}
+
+// NewMarketplaceRegistry creates an empty MarketplaceRegistry; register
+// backends with Register before use.
+func NewMarketplaceRegistry() *MarketplaceRegistry {
+	return &MarketplaceRegistry{
+		entries:   make(map[string]Marketplace),
+		manifests: make(map[string]MarketplaceManifest),
+	}
+}
+
+// Register adds mp under manifest.Name, overwriting any existing
+// registration for that name.
+func (r *MarketplaceRegistry) Register(mp Marketplace, manifest MarketplaceManifest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[manifest.Name] = mp
+	r.manifests[manifest.Name] = manifest
+}
+
+// Get looks up the Marketplace registered as name.
+func (r *MarketplaceRegistry) Get(name string) (Marketplace, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	mp, ok := r.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("no marketplace registered as %q", name)
+	}
+	return mp, nil
+}
+
+// List returns every registered marketplace's manifest, for the discovery
+// endpoint. Order is not guaranteed.
+func (r *MarketplaceRegistry) List() []MarketplaceManifest {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	manifests := make([]MarketplaceManifest, 0, len(r.manifests))
+	for _, m := range r.manifests {
+		manifests = append(manifests, m)
+	}
+	return manifests
+}
+
+// amazonASINFromURL extracts the ASIN out of an Amazon product URL's
+// "/dp/<ASIN>" segment; shared with AmazonShop's page-parsing regex.
+var amazonASINFromURL = regexp.MustCompile(`/dp/([A-Z0-9]{10})`)
+
+// amazonMarketplace adapts ScraperService to the Marketplace interface as
+// the registry's sole built-in. Every Amazon region, Steam, and the
+// generic Shopify fallback are still dispatched by ShopManager underneath
+// exactly as before this package existed; amazonMarketplace just gives
+// that stack a name plugins can be registered alongside.
+type amazonMarketplace struct {
+	scraper *ScraperService
+}
+
+// NewAmazonMarketplace wraps scraper as the registry's built-in "amazon"
+// Marketplace.
+func NewAmazonMarketplace(scraper *ScraperService) Marketplace {
+	return &amazonMarketplace{scraper: scraper}
+}
+
+func (m *amazonMarketplace) Name() string { return "amazon" }
+
+func (m *amazonMarketplace) IdentifierFromURL(rawURL string) (string, error) {
+	matches := amazonASINFromURL.FindStringSubmatch(rawURL)
+	if matches == nil {
+		return "", fmt.Errorf("no ASIN found in URL %q", rawURL)
+	}
+	return matches[1], nil
+}
+
+func (m *amazonMarketplace) ScrapeProduct(requestID, rawURL string, opts ...ScrapeOption) (*AmazonProduct, error) {
+	return m.scraper.ScrapeProduct(requestID, rawURL, opts...)
+}
+
+func (m *amazonMarketplace) SearchProducts(requestID, query string, opts SearchOptions) ([]*AmazonProduct, error) {
+	return m.scraper.shops.Search(requestID, "amazon.com", query, opts)
+}
+
+func (m *amazonMarketplace) BulkScrapeProducts(requestID string, urls []string) (*BulkResult, error) {
+	return m.scraper.BulkScrapeProducts(requestID, urls)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PluginManifest is the shape of a marketplace plugin's plugin.yaml: enough
+// for the host to launch the plugin process, label it for discovery via
+// MarketplaceManifest, and partition proxies to the regions it needs (see
+// ProxyService.GetClientForMarketplace).
+type PluginManifest struct {
+	Name                 string   `yaml:"name"`
+	Command              string   `yaml:"command"`
+	Args                 []string `yaml:"args"`
+	Capabilities         []string `yaml:"capabilities"`
+	RateLimitPerMinute   int      `yaml:"rate_limit_per_minute"`
+	RequiredProxyRegions []string `yaml:"required_proxy_regions"`
+}
+
+// LoadPluginManifest reads and parses path as a PluginManifest.
+func LoadPluginManifest(path string) (*PluginManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin manifest: %w", err)
+	}
+
+	var manifest PluginManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid plugin manifest YAML: %w", err)
+	}
+	if manifest.Name == "" || manifest.Command == "" {
+		return nil, fmt.Errorf("plugin manifest missing required name/command")
+	}
+
+	return &manifest, nil
+}
+
+// LoadPluginsFromDir discovers and launches every marketplace plugin under
+// dir (one subdirectory per plugin, each containing a plugin.yaml
+// manifest), registering each into registry. A plugin that fails to load
+// is logged and skipped rather than failing the whole directory, so one
+// bad plugin doesn't keep every other marketplace from starting up.
+func LoadPluginsFromDir(registry *MarketplaceRegistry, dir string) {
+	if dir == "" {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.Printf("failed to read marketplace plugin directory %s: %v", dir, err)
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		manifestPath := filepath.Join(dir, entry.Name(), "plugin.yaml")
+		manifest, err := LoadPluginManifest(manifestPath)
+		if err != nil {
+			log.Printf("skipping marketplace plugin %s: %v", entry.Name(), err)
+			continue
+		}
+
+		mp, err := LoadPlugin(manifest)
+		if err != nil {
+			log.Printf("failed to load marketplace plugin %s: %v", manifest.Name, err)
+			continue
+		}
+
+		registry.Register(mp, MarketplaceManifest{
+			Name:                 manifest.Name,
+			Capabilities:         manifest.Capabilities,
+			RateLimitPerMinute:   manifest.RateLimitPerMinute,
+			RequiredProxyRegions: manifest.RequiredProxyRegions,
+		})
+		log.Printf("registered marketplace plugin %q from %s", manifest.Name, manifestPath)
+	}
+}
+
+// pluginMarketplace is a Marketplace served by an external process over
+// net/rpc, launched and handshaked the way hashicorp/go-plugin does: the
+// plugin prints a single "network|address" line to stdout once it's
+// listening (e.g. "unix|/tmp/revcopy-walmart.sock"), and the host dials
+// that address for the life of the process. Keeping the transport to
+// net/rpc, rather than extending the gRPC surface in api/proto, lets a
+// plugin be a standalone binary with no dependency on this repo's
+// generated protobuf code.
+type pluginMarketplace struct {
+	name string
+	cmd  *exec.Cmd
+	rpc  *rpc.Client
+}
+
+// LoadPlugin launches manifest.Command as a subprocess, reads its
+// handshake line off stdout, and dials the address it advertised. The
+// process is left running for the life of the returned Marketplace.
+func LoadPlugin(manifest *PluginManifest) (Marketplace, error) {
+	cmd := exec.Command(manifest.Command, manifest.Args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin stdout: %w", err)
+	}
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", manifest.Command, err)
+	}
+
+	handshake, err := readPluginHandshake(stdout)
+	if err != nil {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("plugin %s failed handshake: %w", manifest.Name, err)
+	}
+
+	network, address, ok := strings.Cut(handshake, "|")
+	if !ok {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("plugin %s sent malformed handshake %q", manifest.Name, handshake)
+	}
+
+	conn, err := net.DialTimeout(network, address, 5*time.Second)
+	if err != nil {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("failed to dial plugin %s at %s %s: %w", manifest.Name, network, address, err)
+	}
+
+	return &pluginMarketplace{name: manifest.Name, cmd: cmd, rpc: rpc.NewClient(conn)}, nil
+}
+
+// readPluginHandshake reads the plugin's single handshake line off stdout.
+func readPluginHandshake(stdout io.Reader) (string, error) {
+	scanner := bufio.NewScanner(stdout)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("plugin exited before sending a handshake")
+	}
+	return strings.TrimSpace(scanner.Text()), nil
+}
+
+// Close disconnects from the plugin and terminates its process; callers
+// should invoke this on shutdown for every Marketplace LoadPlugin returned.
+func (p *pluginMarketplace) Close() {
+	p.rpc.Close()
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+	p.cmd.Wait()
+}
+
+func (p *pluginMarketplace) Name() string { return p.name }
+
+// pluginIdentifierArgs/pluginScrapeArgs/pluginSearchArgs/pluginBulkArgs are
+// the net/rpc argument shapes a plugin's ScraperPlugin.* methods accept;
+// pluginReply wraps every RPC's result since net/rpc requires exactly one
+// reply value per call.
+type pluginIdentifierArgs struct {
+	URL string
+}
+
+type pluginScrapeArgs struct {
+	RequestID      string
+	URL            string
+	MaxReviewPages int
+}
+
+type pluginSearchArgs struct {
+	RequestID string
+	Query     string
+	Opts      SearchOptions
+}
+
+type pluginBulkArgs struct {
+	RequestID string
+	URLs      []string
+}
+
+type pluginReply struct {
+	Identifier string
+	Product    *AmazonProduct
+	Products   []*AmazonProduct
+	Bulk       *BulkResult
+	Err        string
+}
+
+func (p *pluginMarketplace) IdentifierFromURL(rawURL string) (string, error) {
+	var reply pluginReply
+	if err := p.rpc.Call("ScraperPlugin.IdentifierFromURL", pluginIdentifierArgs{URL: rawURL}, &reply); err != nil {
+		return "", fmt.Errorf("plugin %s: %w", p.name, err)
+	}
+	if reply.Err != "" {
+		return "", fmt.Errorf("plugin %s: %s", p.name, reply.Err)
+	}
+	return reply.Identifier, nil
+}
+
+func (p *pluginMarketplace) ScrapeProduct(requestID, rawURL string, opts ...ScrapeOption) (*AmazonProduct, error) {
+	cfg := applyScrapeOptions(opts)
+	args := pluginScrapeArgs{RequestID: requestID, URL: rawURL, MaxReviewPages: cfg.maxReviewPages}
+
+	var reply pluginReply
+	if err := p.rpc.Call("ScraperPlugin.ScrapeProduct", args, &reply); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", p.name, err)
+	}
+	if reply.Err != "" {
+		return nil, fmt.Errorf("plugin %s: %s", p.name, reply.Err)
+	}
+	return reply.Product, nil
+}
+
+func (p *pluginMarketplace) SearchProducts(requestID, query string, opts SearchOptions) ([]*AmazonProduct, error) {
+	var reply pluginReply
+	args := pluginSearchArgs{RequestID: requestID, Query: query, Opts: opts}
+	if err := p.rpc.Call("ScraperPlugin.SearchProducts", args, &reply); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", p.name, err)
+	}
+	if reply.Err != "" {
+		return nil, fmt.Errorf("plugin %s: %s", p.name, reply.Err)
+	}
+	return reply.Products, nil
+}
+
+func (p *pluginMarketplace) BulkScrapeProducts(requestID string, urls []string) (*BulkResult, error) {
+	var reply pluginReply
+	if err := p.rpc.Call("ScraperPlugin.BulkScrapeProducts", pluginBulkArgs{RequestID: requestID, URLs: urls}, &reply); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", p.name, err)
+	}
+	if reply.Err != "" {
+		return nil, fmt.Errorf("plugin %s: %s", p.name, reply.Err)
+	}
+	return reply.Bulk, nil
+}
@@ -0,0 +1,29 @@
+package services
+
+import "golang.org/x/crypto/bcrypt"
+
+// hashPassword hashes password with bcrypt at the given cost. cost <= 0
+// falls back to bcrypt.DefaultCost.
+func hashPassword(password string, cost int) (string, error) {
+	if cost <= 0 {
+		cost = bcrypt.DefaultCost
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// checkPassword reports whether password matches hash.
+func checkPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// isBcryptHash reports whether value looks like a bcrypt hash, so the
+// startup migration can tell an already-hashed password from a legacy
+// plaintext one.
+func isBcryptHash(value string) bool {
+	return len(value) >= 4 && value[0] == '$' && value[1] == '2' &&
+		(value[2] == 'a' || value[2] == 'b' || value[2] == 'y') && value[3] == '$'
+}
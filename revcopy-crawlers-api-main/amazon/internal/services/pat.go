@@ -0,0 +1,238 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// patPrefix identifies a bearer credential as a personal access token rather
+// than a JWT; middleware.AuthRequired switches on it to pick the right
+// validation path.
+const patPrefix = "pat_"
+
+// PersonalAccessToken is the metadata persisted for a minted token. The raw
+// token value is never stored, only its SHA-256 hash; Prefix keeps enough of
+// the token visible (e.g. in a list view) for the owner to recognize it.
+type PersonalAccessToken struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Username   string     `json:"username"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	TokenHash  string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// Expired reports whether t is past its expiry; a nil ExpiresAt never expires.
+func (t *PersonalAccessToken) Expired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+// TokenStore persists personal access tokens so a database-backed
+// implementation can replace the in-memory default without touching
+// AuthService.
+type TokenStore interface {
+	Create(token *PersonalAccessToken) error
+	Get(id string) (*PersonalAccessToken, error)
+	FindByHash(hash string) (*PersonalAccessToken, error)
+	ListByUser(userID string) ([]*PersonalAccessToken, error)
+	Delete(id string) error
+	Touch(id string, usedAt time.Time) error
+}
+
+// InMemoryTokenStore is the default TokenStore, holding tokens in a map for
+// the lifetime of the process (use a database in production).
+type InMemoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]*PersonalAccessToken
+}
+
+// NewInMemoryTokenStore creates an empty in-memory token store.
+func NewInMemoryTokenStore() *InMemoryTokenStore {
+	return &InMemoryTokenStore{tokens: make(map[string]*PersonalAccessToken)}
+}
+
+func (s *InMemoryTokenStore) Create(token *PersonalAccessToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token.ID] = token
+	return nil
+}
+
+func (s *InMemoryTokenStore) Get(id string) (*PersonalAccessToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	token, exists := s.tokens[id]
+	if !exists {
+		return nil, errors.New("token not found")
+	}
+	return token, nil
+}
+
+func (s *InMemoryTokenStore) FindByHash(hash string) (*PersonalAccessToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, token := range s.tokens {
+		if token.TokenHash == hash {
+			return token, nil
+		}
+	}
+	return nil, errors.New("token not found")
+}
+
+func (s *InMemoryTokenStore) ListByUser(userID string) ([]*PersonalAccessToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tokens := make([]*PersonalAccessToken, 0)
+	for _, token := range s.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (s *InMemoryTokenStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.tokens[id]; !exists {
+		return errors.New("token not found")
+	}
+	delete(s.tokens, id)
+	return nil
+}
+
+func (s *InMemoryTokenStore) Touch(id string, usedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, exists := s.tokens[id]
+	if !exists {
+		return errors.New("token not found")
+	}
+	token.LastUsedAt = &usedAt
+	return nil
+}
+
+// CreatePATRequest represents a personal access token creation request.
+type CreatePATRequest struct {
+	Name      string   `json:"name" binding:"required"`
+	Scopes    []string `json:"scopes" binding:"required"`
+	ExpiresIn *int64   `json:"expires_in,omitempty"` // seconds; omitted means no expiry
+}
+
+// CreatePATResponse is returned once, at creation time, since it is the only
+// moment the raw token value is ever available.
+type CreatePATResponse struct {
+	Token string `json:"token"`
+	*PersonalAccessToken
+}
+
+// CreatePAT mints a new personal access token for the given user, returning
+// the raw "pat_<base64>" value alongside its metadata. Only the SHA-256
+// hash of the token is persisted.
+func (s *AuthService) CreatePAT(userID, username string, req CreatePATRequest) (*CreatePATResponse, error) {
+	for _, scope := range req.Scopes {
+		if !s.permissionExists(scope) {
+			return nil, errors.New("unknown scope: " + scope)
+		}
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := patPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	hash := hashPAT(token)
+
+	var expiresAt *time.Time
+	if req.ExpiresIn != nil {
+		t := time.Now().Add(time.Duration(*req.ExpiresIn) * time.Second)
+		expiresAt = &t
+	}
+
+	pat := &PersonalAccessToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Username:  username,
+		Name:      req.Name,
+		Prefix:    token[:len(patPrefix)+8] + "...",
+		TokenHash: hash,
+		Scopes:    req.Scopes,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+
+	if err := s.tokens.Create(pat); err != nil {
+		return nil, err
+	}
+
+	return &CreatePATResponse{Token: token, PersonalAccessToken: pat}, nil
+}
+
+// ListPATs returns the metadata (never the raw value) of every token owned
+// by userID.
+func (s *AuthService) ListPATs(userID string) ([]*PersonalAccessToken, error) {
+	return s.tokens.ListByUser(userID)
+}
+
+// RevokePAT deletes a token by ID, refusing to touch tokens owned by
+// another user.
+func (s *AuthService) RevokePAT(userID, id string) error {
+	token, err := s.tokens.Get(id)
+	if err != nil {
+		return err
+	}
+	if token.UserID != userID {
+		return errors.New("token not found")
+	}
+	return s.tokens.Delete(id)
+}
+
+// ValidatePAT looks up the token store by hash and returns the token's
+// metadata if it exists, is not expired, and marks it as just used.
+func (s *AuthService) ValidatePAT(token string) (*PersonalAccessToken, error) {
+	pat, err := s.tokens.FindByHash(hashPAT(token))
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+	if pat.Expired() {
+		return nil, errors.New("token expired")
+	}
+
+	now := time.Now()
+	_ = s.tokens.Touch(pat.ID, now)
+
+	return pat, nil
+}
+
+// permissionExists reports whether scope is a permission granted by any
+// registered role, so CreatePAT can reject typos before they're persisted.
+func (s *AuthService) permissionExists(scope string) bool {
+	for _, role := range s.roles {
+		if role.Has(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func hashPAT(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsPAT reports whether token carries the "pat_" prefix used by personal
+// access tokens, as opposed to a JWT.
+func IsPAT(token string) bool {
+	return len(token) > len(patPrefix) && token[:len(patPrefix)] == patPrefix
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresProductStore is an alternate ProductStore backed by Postgres, for
+// deployments that would rather not run Redis. Entries are stored in the
+// "product_cache" table defined in database/migrations.
+type PostgresProductStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresProductStore connects to databaseURL and returns a ProductStore
+// backed by Postgres.
+func NewPostgresProductStore(ctx context.Context, databaseURL string) (*PostgresProductStore, error) {
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to product cache database: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping product cache database: %w", err)
+	}
+
+	return &PostgresProductStore{pool: pool}, nil
+}
+
+// Close releases the underlying connection pool.
+func (s *PostgresProductStore) Close() {
+	s.pool.Close()
+}
+
+func (s *PostgresProductStore) Get(marketplace, asin string) (*CachedProduct, error) {
+	row := s.pool.QueryRow(context.Background(), `
+		SELECT product, last_refreshed FROM product_cache
+		WHERE marketplace = $1 AND asin = $2 AND expires_at > now()`,
+		marketplace, asin)
+
+	var raw []byte
+	var cached CachedProduct
+	if err := row.Scan(&raw, &cached.LastRefreshed); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotCached
+		}
+		return nil, err
+	}
+
+	var product AmazonProduct
+	if err := json.Unmarshal(raw, &product); err != nil {
+		return nil, err
+	}
+	cached.Product = &product
+	return &cached, nil
+}
+
+func (s *PostgresProductStore) Set(marketplace, asin string, product *AmazonProduct, ttl time.Duration) error {
+	raw, err := json.Marshal(product)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = s.pool.Exec(context.Background(), `
+		INSERT INTO product_cache (marketplace, asin, product, last_refreshed, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (marketplace, asin) DO UPDATE
+		SET product = $3, last_refreshed = $4, expires_at = $5`,
+		marketplace, asin, raw, now, now.Add(ttl))
+	return err
+}
+
+func (s *PostgresProductStore) Delete(marketplace, asin string) error {
+	_, err := s.pool.Exec(context.Background(),
+		"DELETE FROM product_cache WHERE marketplace = $1 AND asin = $2", marketplace, asin)
+	return err
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresUserRepository is the production UserRepository, backed by the
+// "users" table defined in database/migrations and queried per
+// database/queries/users.sql.
+type PostgresUserRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresUserRepository connects to databaseURL and returns a
+// UserRepository backed by Postgres.
+func NewPostgresUserRepository(ctx context.Context, databaseURL string) (*PostgresUserRepository, error) {
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to user database: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping user database: %w", err)
+	}
+
+	return &PostgresUserRepository{pool: pool}, nil
+}
+
+// Close releases the underlying connection pool.
+func (r *PostgresUserRepository) Close() {
+	r.pool.Close()
+}
+
+const userColumns = "id, username, password, role, active, auth_type, oauth_subject, created_at, updated_at"
+
+func (r *PostgresUserRepository) Get(username string) (*User, error) {
+	row := r.pool.QueryRow(context.Background(),
+		"SELECT "+userColumns+" FROM users WHERE username = $1", username)
+	return scanUser(row)
+}
+
+func (r *PostgresUserRepository) FindByOAuthSubject(subject string) (*User, error) {
+	row := r.pool.QueryRow(context.Background(),
+		"SELECT "+userColumns+" FROM users WHERE oauth_subject = $1", subject)
+	return scanUser(row)
+}
+
+func (r *PostgresUserRepository) List() ([]*User, error) {
+	rows, err := r.pool.Query(context.Background(), "SELECT "+userColumns+" FROM users ORDER BY username")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *PostgresUserRepository) Create(user *User) error {
+	_, err := r.pool.Exec(context.Background(), `
+		INSERT INTO users (`+userColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		user.ID, user.Username, user.Password, user.Role, user.Active,
+		user.AuthType, nullableString(user.OAuthSubject), user.CreatedAt, user.UpdatedAt)
+	return err
+}
+
+func (r *PostgresUserRepository) Update(user *User) error {
+	tag, err := r.pool.Exec(context.Background(), `
+		UPDATE users
+		SET password = $2, role = $3, active = $4, auth_type = $5, oauth_subject = $6, updated_at = $7
+		WHERE username = $1`,
+		user.Username, user.Password, user.Role, user.Active,
+		user.AuthType, nullableString(user.OAuthSubject), user.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) Delete(username string) error {
+	tag, err := r.pool.Exec(context.Background(), "DELETE FROM users WHERE username = $1", username)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// rowScanner is satisfied by both pgx.Row (QueryRow) and pgx.Rows (Query),
+// so scanUser can read either one.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (*User, error) {
+	var user User
+	var oauthSubject *string
+
+	err := row.Scan(&user.ID, &user.Username, &user.Password, &user.Role, &user.Active,
+		&user.AuthType, &oauthSubject, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	if oauthSubject != nil {
+		user.OAuthSubject = *oauthSubject
+	}
+	return &user, nil
+}
+
+// nullableString converts an empty string to a nil driver value so an unset
+// OAuthSubject is stored as SQL NULL, keeping the column's UNIQUE constraint
+// from colliding across multiple password-only accounts.
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
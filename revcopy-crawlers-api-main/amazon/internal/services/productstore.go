@@ -0,0 +1,32 @@
+package services
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrProductNotCached is returned by ProductStore.Get when no (fresh) entry
+// exists for the given marketplace+ASIN.
+var ErrProductNotCached = errors.New("product not cached")
+
+// CachedProduct wraps a scraped AmazonProduct with the cache metadata
+// ProductStore persists alongside it.
+type CachedProduct struct {
+	Product       *AmazonProduct `json:"product"`
+	LastRefreshed time.Time      `json:"last_refreshed"`
+}
+
+// ProductStore persists scraped products so GetAmazonProduct can serve a
+// cached copy instead of re-scraping on every request. Keys are
+// marketplace + ASIN (e.g. "amazon.com"+"B0BSHF7WHW"), since the same ASIN
+// can resolve to a different price/availability per storefront.
+type ProductStore interface {
+	// Get returns the cached product for marketplace+asin, or
+	// ErrProductNotCached if nothing is cached or the entry has expired.
+	Get(marketplace, asin string) (*CachedProduct, error)
+	// Set write-through caches product for marketplace+asin, expiring it
+	// after ttl.
+	Set(marketplace, asin string, product *AmazonProduct, ttl time.Duration) error
+	// Delete evicts the cache entry for marketplace+asin, if any.
+	Delete(marketplace, asin string) error
+}
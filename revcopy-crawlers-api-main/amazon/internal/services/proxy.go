@@ -1,16 +1,60 @@
 package services
 
 import (
+	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/google/uuid"
 )
 
+// HealthCheckConfig controls the background proxy health checker
+type HealthCheckConfig struct {
+	Interval    time.Duration // how often to test each proxy
+	CheckURL    string        // URL used to verify connectivity and read back the egress IP (e.g. an IP-echo service)
+	CanaryURL   string        // product page probed alongside CheckURL so a CAPTCHA wall is caught, not just dropped connections
+	Timeout     time.Duration // per-check timeout
+	MaxFailures int           // consecutive failures before a proxy is marked degraded
+	BanFailures int           // consecutive failures before a proxy is marked banned outright
+	MaxBackoff  time.Duration // cap on the exponential backoff between checks of a failing proxy
+}
+
+// DefaultHealthCheckConfig returns sane defaults for the health checker
+func DefaultHealthCheckConfig() HealthCheckConfig {
+	return HealthCheckConfig{
+		Interval:    time.Minute,
+		CheckURL:    "https://api.ipify.org",
+		CanaryURL:   "https://www.amazon.com/dp/B08N5WRWNW",
+		Timeout:     10 * time.Second,
+		MaxFailures: 3,
+		BanFailures: 8,
+		MaxBackoff:  10 * time.Minute,
+	}
+}
+
+// ProxyHealthState classifies a proxy's consecutive health-check outcomes.
+// Degraded proxies stay eligible for selection, just heavily downweighted;
+// banned proxies are excluded entirely until they recover.
+type ProxyHealthState string
+
+const (
+	ProxyHealthHealthy  ProxyHealthState = "healthy"
+	ProxyHealthDegraded ProxyHealthState = "degraded"
+	ProxyHealthBanned   ProxyHealthState = "banned"
+)
+
 // ProxyService handles proxy operations
 type ProxyService struct {
 	client          *http.Client
@@ -19,6 +63,134 @@ type ProxyService struct {
 	userProxies     map[string]string      // User to proxy mapping
 	defaultProxyID  string                 // Default proxy ID
 	mutex           sync.RWMutex           // Thread safety
+
+	healthCheck   HealthCheckConfig
+	bypassDomains []string // domains routed directly, bypassing the proxy pool
+
+	stickyMu      sync.Mutex
+	stickySessions map[string]string // (username, targetHost) -> proxyID
+
+	// proxyGroups and userGroups back the /api/v1/proxy-groups feature (see
+	// proxygroup.go): a group resolves to one concrete proxy per lookup,
+	// and a user can be assigned a group instead of a single proxy.
+	proxyGroups map[string]*ProxyGroup
+	userGroups  map[string]string // username -> groupID
+
+	// userBypass holds a per-assignment no_proxy override (see
+	// AssignProxyToUser), taking precedence over the assigned proxy's own
+	// NoProxy list.
+	userBypass map[string]*bypassMatcher
+
+	rrCounter uint64 // round-robin cursor for weighted dispatch
+
+	stopHealthCheck chan struct{}
+
+	analytics *AnalyticsService // optional; reports proxy/assignment gauges when set
+
+	store ProxyStore // persists proxy configuration across restarts
+
+	// fileConfigPath, if set, names a proxies.yaml bootstrap file that's
+	// re-synced into proxies on every boot and on every ReloadProxiesFromFile
+	// call; see proxyfilesync.go.
+	fileConfigPath string
+	fileWatcher    *fsnotify.Watcher
+
+	notifyMu sync.Mutex
+	notifyCh chan struct{} // closed and replaced on every proxy config mutation, for long-poll waiters
+}
+
+// SetAnalyticsService wires an AnalyticsService so proxy health and
+// in-flight/assignment counts are exported as Prometheus gauges.
+func (s *ProxyService) SetAnalyticsService(analytics *AnalyticsService) {
+	s.mutex.Lock()
+	s.analytics = analytics
+	s.mutex.Unlock()
+}
+
+// reportInFlight reports entry's current in-flight request count (as
+// tracked by the instrumented RoundTripper's Stats.CurConns) to analytics.
+func (s *ProxyService) reportInFlight(entry *ProxyEntry, inFlight int64) {
+	s.mutex.RLock()
+	analytics := s.analytics
+	s.mutex.RUnlock()
+
+	if analytics != nil {
+		analytics.SetProxyInFlight(entry.ID, int(inFlight))
+	}
+}
+
+// reportAssignmentCount reports the current number of user-proxy assignments
+// to analytics. Callers must hold s.mutex.
+func (s *ProxyService) reportAssignmentCount() {
+	if s.analytics != nil {
+		s.analytics.SetUserProxyAssignments(len(s.userProxies))
+	}
+}
+
+// notifyChanged wakes every goroutine currently blocked in WaitForChange.
+// Callers may hold s.mutex or not; notifyMu is independent of it.
+func (s *ProxyService) notifyChanged() {
+	s.notifyMu.Lock()
+	close(s.notifyCh)
+	s.notifyCh = make(chan struct{})
+	s.notifyMu.Unlock()
+}
+
+// WaitForChange blocks until the next proxy config mutation, ctx is
+// cancelled, or timeout elapses — whichever comes first. It returns true iff
+// woken by a mutation, so callers can distinguish a change from a timeout.
+func (s *ProxyService) WaitForChange(ctx context.Context, timeout time.Duration) bool {
+	s.notifyMu.Lock()
+	ch := s.notifyCh
+	s.notifyMu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ch:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return false
+	}
+}
+
+// HashProxy returns a stable hash of proxyID's externally-visible config,
+// for long-poll callers to detect changes without comparing full payloads.
+// UpdatedAt is excluded since it changes on every write even when nothing
+// the caller can see actually changed.
+func (s *ProxyService) HashProxy(proxyID string) (string, error) {
+	proxy, err := s.GetProxy(proxyID)
+	if err != nil {
+		return "", err
+	}
+	return hashProxyResponse(proxy), nil
+}
+
+// HashProxies returns a stable hash of every stored proxy's externally-visible
+// config, order-independent, for long-poll callers watching the full list.
+func (s *ProxyService) HashProxies() string {
+	proxies := s.ListProxies()
+	sort.Slice(proxies, func(i, j int) bool { return proxies[i].ID < proxies[j].ID })
+
+	h := sha256.New()
+	for _, p := range proxies {
+		io.WriteString(h, hashProxyResponse(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashProxyResponse hashes the fields of p that are visible to API callers,
+// deliberately excluding the volatile UpdatedAt timestamp.
+func hashProxyResponse(p *ProxyResponse) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%s|%s|%s|%t|%s|%s",
+		p.ID, p.Name, p.Username, p.Host, p.Port, p.Active,
+		p.CreatedAt.UTC().Format(time.RFC3339Nano), p.CreatedBy)
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 // ProxyConfig holds proxy configuration
@@ -42,45 +214,208 @@ type ProxyEntry struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	CreatedBy   string    `json:"created_by"`
+
+	// Marketplaces restricts this proxy to the named marketplaces (e.g.
+	// "amazon", "walmart"); empty means it's available to every marketplace.
+	Marketplaces []string `json:"marketplaces,omitempty"`
+
+	// Health-check state, updated by the background health checker
+	Healthy             bool             `json:"healthy"`
+	HealthState         ProxyHealthState `json:"health_state,omitempty"`
+	LastCheckedAt       time.Time        `json:"last_checked_at,omitempty"`
+	LastSuccessAt       time.Time        `json:"last_success_at,omitempty"`
+	LastLatencyMs       int64            `json:"last_latency_ms"`
+	ConsecutiveFailures int              `json:"consecutive_failures"`
+	EgressIP            string           `json:"egress_ip,omitempty"`
+	CaptchaCount        int64            `json:"captcha_count"`
+
+	// Draining is set by DrainProxy to stop new assignments while requests
+	// already dispatched through this proxy finish on their own.
+	Draining bool `json:"draining"`
+
+	// FromFile marks a proxy provisioned by the proxies.yaml config file (see
+	// ProxyService.ReloadProxiesFromFile). Config-file entries are re-synced
+	// on every boot and file change, and can't be deleted via the API.
+	FromFile bool `json:"from_file"`
+
+	// NoProxy is this proxy's default bypass list: a comma-separated list of
+	// hostnames, CIDRs, and leading-dot domain suffixes that should skip the
+	// proxy even when it's assigned to a user (see ResolveUserProxy). A
+	// per-assignment no_proxy list set via AssignProxyToUser takes
+	// precedence over this one.
+	NoProxy        string `json:"no_proxy,omitempty"`
+	noProxyMatcher *bypassMatcher
+
+	// nextCheckAt is when the health checker should next probe this proxy;
+	// it advances with exponential backoff on failure so a banned proxy
+	// doesn't keep consuming check capacity every Interval.
+	nextCheckAt time.Time
+
+	// Traffic statistics, updated atomically by the instrumented RoundTripper
+	// returned from GetClientForUser/GetClientForKey/getClient.
+	Stats ProxyStatistics `json:"stats"`
+
+	// concurrentLimit caps in-flight requests dispatched through this proxy;
+	// enforced by instrumentedTransport.RoundTrip.
+	concurrentLimit chan struct{}
+
+	// trafficMu guards trafficBuckets, which is read/written far more often
+	// than the rest of the entry so it gets its own lock rather than riding
+	// on ProxyService.mutex.
+	trafficMu      sync.Mutex
+	trafficBuckets [ringSlots]proxyTrafficBucket
+
+	// delayMu guards delayProbes, the ring of recent on-demand/background
+	// delay-probe results (see ProxyService.TestProxyDelay).
+	delayMu     sync.Mutex
+	delayProbes [delayCacheSize]delayProbeResult
+	delayCount  int
+	delayCursor int
+}
+
+// delayCacheSize bounds how many delay-probe results are kept per proxy.
+const delayCacheSize = 5
+
+// delayCacheTTL is how long a cached delay-probe result stays fresh enough
+// for ListProxies to surface as last_delay_ms/last_checked_at.
+const delayCacheTTL = 5 * time.Minute
+
+// delayProbeResult is one GET-through-proxy delay measurement.
+type delayProbeResult struct {
+	DelayMs int64     `json:"delay_ms"`
+	Status  int       `json:"status,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// recordDelayProbe appends result to entry's delay-probe ring, overwriting
+// the oldest entry once full.
+func (entry *ProxyEntry) recordDelayProbe(result delayProbeResult) {
+	entry.delayMu.Lock()
+	defer entry.delayMu.Unlock()
+
+	entry.delayProbes[entry.delayCursor%delayCacheSize] = result
+	entry.delayCursor++
+	if entry.delayCount < delayCacheSize {
+		entry.delayCount++
+	}
+}
+
+// latestDelay returns entry's most recent delay-probe result, if one was
+// recorded within delayCacheTTL.
+func (entry *ProxyEntry) latestDelay() (delayProbeResult, bool) {
+	entry.delayMu.Lock()
+	defer entry.delayMu.Unlock()
+
+	if entry.delayCount == 0 {
+		return delayProbeResult{}, false
+	}
+
+	latest := entry.delayProbes[(entry.delayCursor-1+delayCacheSize)%delayCacheSize]
+	if time.Since(latest.At) > delayCacheTTL {
+		return delayProbeResult{}, false
+	}
+	return latest, true
+}
+
+// ProxyStatistics holds cumulative traffic counters for a proxy, safe for
+// concurrent atomic access.
+type ProxyStatistics struct {
+	TrafficIn    int64     `json:"traffic_in_bytes"`
+	TrafficOut   int64     `json:"traffic_out_bytes"`
+	CurConns     int64     `json:"current_connections"`
+	SuccessCount int64     `json:"success_count"`
+	FailureCount int64     `json:"failure_count"`
+	LastUsed     time.Time `json:"last_used,omitempty"`
+
+	// lastUsedUnixNano backs LastUsed for concurrent access: time.Time is a
+	// multi-word value, so the instrumented RoundTripper stores it here with
+	// atomic.StoreInt64 instead of writing LastUsed directly, which would
+	// race with the atomic.LoadInt64 reads elsewhere. LastUsed itself is
+	// only ever populated from RecordUse/LastUsedAt, for JSON output.
+	lastUsedUnixNano int64
+}
+
+// RecordUse atomically stores t as this proxy's last-used time.
+func (s *ProxyStatistics) RecordUse(t time.Time) {
+	atomic.StoreInt64(&s.lastUsedUnixNano, t.UnixNano())
+}
+
+// LastUsedAt atomically reads the last-used time stored by RecordUse, or
+// the zero time if the proxy has never been used.
+func (s *ProxyStatistics) LastUsedAt() time.Time {
+	nanos := atomic.LoadInt64(&s.lastUsedUnixNano)
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// proxyTrafficBucket is one per-minute slot of traffic/latency history for a
+// proxy, used to back the dashboard's traffic-over-time endpoint.
+type proxyTrafficBucket struct {
+	minute         int64
+	requests       int64
+	trafficIn      int64
+	trafficOut     int64
+	totalLatencyMs float64
 }
 
 // CreateProxyRequest represents proxy creation request
 type CreateProxyRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
-	Host     string `json:"host" binding:"required"`
-	Port     string `json:"port" binding:"required"`
-	Active   bool   `json:"active"`
+	Name         string   `json:"name" binding:"required"`
+	Username     string   `json:"username" binding:"required"`
+	Password     string   `json:"password" binding:"required"`
+	Host         string   `json:"host" binding:"required"`
+	Port         string   `json:"port" binding:"required"`
+	Active       bool     `json:"active"`
+	Marketplaces []string `json:"marketplaces,omitempty"`
+	// NoProxy is a comma-separated list of hostnames, CIDRs, and leading-dot
+	// domain suffixes that bypass this proxy for every user it's assigned
+	// to (see ProxyEntry.NoProxy).
+	NoProxy string `json:"no_proxy,omitempty"`
 }
 
 // UpdateProxyRequest represents proxy update request
 type UpdateProxyRequest struct {
-	Name     string `json:"name,omitempty"`
-	Username string `json:"username,omitempty"`
-	Password string `json:"password,omitempty"`
-	Host     string `json:"host,omitempty"`
-	Port     string `json:"port,omitempty"`
-	Active   *bool  `json:"active,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Username     string   `json:"username,omitempty"`
+	Password     string   `json:"password,omitempty"`
+	Host         string   `json:"host,omitempty"`
+	Port         string   `json:"port,omitempty"`
+	Active       *bool    `json:"active,omitempty"`
+	Marketplaces []string `json:"marketplaces,omitempty"`
+	NoProxy      string   `json:"no_proxy,omitempty"`
 }
 
 // ProxyResponse represents proxy response (without sensitive data)
 type ProxyResponse struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Username  string    `json:"username"`
-	Host      string    `json:"host"`
-	Port      string    `json:"port"`
-	Active    bool      `json:"active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	CreatedBy string    `json:"created_by"`
-}
-
-// AssignProxyRequest represents user-proxy assignment request
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Username      string    `json:"username"`
+	Host          string    `json:"host"`
+	Port          string    `json:"port"`
+	Active        bool      `json:"active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedBy     string    `json:"created_by"`
+	Marketplaces  []string  `json:"marketplaces,omitempty"`
+	LastDelayMs   int64     `json:"last_delay_ms"`
+	LastCheckedAt time.Time `json:"last_checked_at,omitempty"`
+	FromFile      bool      `json:"from_file"`
+	NoProxy       string    `json:"no_proxy,omitempty"`
+}
+
+// AssignProxyRequest represents user-proxy assignment request. Exactly one
+// of ProxyID or GroupID must be set: ProxyID pins the user to a single
+// proxy, GroupID routes them through a ProxyGroup's resolution strategy
+// instead (see proxygroup.go). NoProxy, if set, overrides the assigned
+// proxy's own NoProxy list for this user only (see ResolveUserProxy).
 type AssignProxyRequest struct {
 	Username string `json:"username" binding:"required"`
-	ProxyID  string `json:"proxy_id" binding:"required"`
+	ProxyID  string `json:"proxy_id,omitempty"`
+	GroupID  string `json:"group_id,omitempty"`
+	NoProxy  string `json:"no_proxy,omitempty"`
 }
 
 // ProxyStatus represents proxy status information
@@ -92,8 +427,26 @@ type ProxyStatus struct {
 	Error     string `json:"error,omitempty"`
 }
 
-// NewProxyService creates a new proxy service
-func NewProxyService() *ProxyService {
+// defaultConcurrentLimit is the number of in-flight requests allowed per proxy
+// before the dispatcher starts queuing callers.
+const defaultConcurrentLimit = 20
+
+// ProxySeedConfig supplies the proxy used to seed the store on first
+// startup (i.e. when no proxies have been persisted yet).
+type ProxySeedConfig struct {
+	Username string
+	Password string
+	Host     string
+	Port     string
+}
+
+// NewProxyService creates a proxy service backed by store. On startup it
+// loads any previously persisted proxies; if the store is empty it seeds it
+// with seeds (skipping any entry whose Host is unset), which also covers
+// migrating the previous in-memory-only default proxy to persistent storage.
+// If fileConfigPath is non-empty, it's then synced in on top (see
+// ReloadProxiesFromFile): those entries win over anything loaded above.
+func NewProxyService(store ProxyStore, seeds []ProxySeedConfig, fileConfigPath string) *ProxyService {
 	service := &ProxyService{
 		client: &http.Client{
 			Timeout: 30 * time.Second,
@@ -106,33 +459,345 @@ func NewProxyService() *ProxyService {
 		config: ProxyConfig{
 			Enabled: false,
 		},
-		proxies:     make(map[string]*ProxyEntry),
-		userProxies: make(map[string]string),
+		proxies:         make(map[string]*ProxyEntry),
+		userProxies:     make(map[string]string),
+		stickySessions:  make(map[string]string),
+		proxyGroups:     make(map[string]*ProxyGroup),
+		userGroups:      make(map[string]string),
+		userBypass:      make(map[string]*bypassMatcher),
+		healthCheck:     DefaultHealthCheckConfig(),
+		stopHealthCheck: make(chan struct{}),
+		store:           store,
+		fileConfigPath:  fileConfigPath,
+		notifyCh:        make(chan struct{}),
 	}
-	
-	// Create default proxy with anvitop credentials
-	service.createDefaultProxy()
-	
+
+	service.loadOrSeed(seeds)
+	service.syncProxiesFromFile()
+
+	go service.runHealthChecker()
+
 	return service
 }
 
-// createDefaultProxy creates the default proxy configuration
-func (s *ProxyService) createDefaultProxy() {
-	defaultProxy := &ProxyEntry{
-		ID:        uuid.New().String(),
-		Name:      "Default Anvitop Proxy",
-		Username:  "anvitop",
-		Password:  "C29UaLSZPx",
-		Host:      "74.124.222.120",
-		Port:      "50100",
-		Active:    true,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-		CreatedBy: "system",
+// loadOrSeed restores proxies from the store, or seeds it with seeds (each
+// entry with a non-empty Host becomes one persisted proxy) if nothing has
+// been persisted yet.
+func (s *ProxyService) loadOrSeed(seeds []ProxySeedConfig) {
+	if s.store != nil {
+		entries, err := s.store.Load()
+		if err != nil {
+			log.Printf("failed to load persisted proxies, starting empty: %v", err)
+		}
+
+		for _, entry := range entries {
+			if entry.concurrentLimit == nil {
+				entry.concurrentLimit = make(chan struct{}, defaultConcurrentLimit)
+			}
+			if entry.NoProxy != "" {
+				if matcher, err := newBypassMatcher(entry.NoProxy); err == nil {
+					entry.noProxyMatcher = matcher
+				} else {
+					log.Printf("proxy %s has an invalid persisted no_proxy list, ignoring: %v", entry.ID, err)
+				}
+			}
+			s.proxies[entry.ID] = entry
+			if s.defaultProxyID == "" {
+				s.defaultProxyID = entry.ID
+			}
+		}
+
+		if len(entries) > 0 {
+			return
+		}
 	}
-	
-	s.proxies[defaultProxy.ID] = defaultProxy
-	s.defaultProxyID = defaultProxy.ID
+
+	seeded := 0
+	for _, seed := range seeds {
+		if seed.Host == "" {
+			continue
+		}
+
+		name := "Default Proxy"
+		if seeded > 0 {
+			name = fmt.Sprintf("Seed Proxy %d", seeded+1)
+		}
+
+		proxy := &ProxyEntry{
+			ID:              uuid.New().String(),
+			Name:            name,
+			Username:        seed.Username,
+			Password:        seed.Password,
+			Host:            seed.Host,
+			Port:            seed.Port,
+			Active:          true,
+			Healthy:         true,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+			CreatedBy:       "system",
+			concurrentLimit: make(chan struct{}, defaultConcurrentLimit),
+		}
+
+		s.proxies[proxy.ID] = proxy
+		if s.defaultProxyID == "" {
+			s.defaultProxyID = proxy.ID
+		}
+
+		if s.store != nil {
+			if err := s.store.Save(proxy); err != nil {
+				log.Printf("failed to persist seed proxy %s: %v", name, err)
+			}
+		}
+
+		seeded++
+	}
+}
+
+// SetBypassDomains configures domains that should be routed directly,
+// bypassing the proxy pool entirely (e.g. internal or low-risk hosts).
+func (s *ProxyService) SetBypassDomains(domains []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.bypassDomains = domains
+}
+
+// isBypassed reports whether targetHost should skip the proxy pool
+func (s *ProxyService) isBypassed(targetHost string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, domain := range s.bypassDomains {
+		if targetHost == domain || strings.HasSuffix(targetHost, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetHealthCheckConfig overrides the background health checker's interval,
+// check URL, timeout, and failure threshold.
+func (s *ProxyService) SetHealthCheckConfig(cfg HealthCheckConfig) {
+	s.mutex.Lock()
+	s.healthCheck = cfg
+	s.mutex.Unlock()
+}
+
+// Stop terminates the background health checker goroutine.
+func (s *ProxyService) Stop() {
+	close(s.stopHealthCheck)
+}
+
+// runHealthChecker periodically tests every active proxy and updates its
+// health state so the dispatcher can skip unhealthy entries.
+func (s *ProxyService) runHealthChecker() {
+	for {
+		s.mutex.RLock()
+		interval := s.healthCheck.Interval
+		s.mutex.RUnlock()
+
+		select {
+		case <-s.stopHealthCheck:
+			return
+		case <-time.After(interval):
+			s.checkAllProxies()
+		}
+	}
+}
+
+// checkAllProxies runs a connectivity+canary probe against every stored
+// proxy that isn't still serving out an exponential backoff from a
+// previous failure.
+func (s *ProxyService) checkAllProxies() {
+	now := time.Now()
+
+	s.mutex.RLock()
+	entries := make([]*ProxyEntry, 0, len(s.proxies))
+	for _, p := range s.proxies {
+		if now.Before(p.nextCheckAt) {
+			continue
+		}
+		entries = append(entries, p)
+	}
+	cfg := s.healthCheck
+	s.mutex.RUnlock()
+
+	for _, entry := range entries {
+		s.checkProxy(entry, cfg)
+	}
+}
+
+// probeResult is the outcome of one checkProxy probe: either a hard failure
+// (err set) or a completed round-trip with its latency, egress IP, and
+// whether the canary page came back CAPTCHA-walled.
+type probeResult struct {
+	err      error
+	latency  time.Duration
+	egressIP string
+	captcha  bool
+}
+
+// checkProxy issues a GET through entry against cfg.CheckURL to measure
+// latency and read back the egress IP, then (if configured) fetches
+// cfg.CanaryURL to catch a CAPTCHA wall that a plain connectivity check
+// would miss entirely.
+func (s *ProxyService) checkProxy(entry *ProxyEntry, cfg HealthCheckConfig) {
+	client, err := s.clientForEntry(entry, cfg.Timeout)
+	if err != nil {
+		s.recordProbe(entry, cfg, probeResult{err: err})
+		return
+	}
+
+	start := time.Now()
+	egressIP, err := probeEgressIP(client, cfg.CheckURL)
+	latency := time.Since(start)
+	if err != nil {
+		s.recordProbe(entry, cfg, probeResult{err: err, latency: latency})
+		return
+	}
+
+	captcha := false
+	if cfg.CanaryURL != "" {
+		blocked, err := probeCanary(client, cfg.CanaryURL)
+		if err != nil {
+			s.recordProbe(entry, cfg, probeResult{err: err, latency: latency, egressIP: egressIP})
+			return
+		}
+		captcha = blocked
+	}
+
+	s.recordProbe(entry, cfg, probeResult{latency: latency, egressIP: egressIP, captcha: captcha})
+}
+
+// probeEgressIP GETs checkURL through client and returns the body (an
+// IP-echo service's whole response is just the caller's IP as text).
+func probeEgressIP(client *http.Client, checkURL string) (string, error) {
+	resp, err := client.Get(checkURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("health check got HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// probeCanary GETs canaryURL through client and reports whether the
+// response looks like a CAPTCHA wall, reusing the same heuristic the
+// scraper's own Fetcher falls back on.
+func probeCanary(client *http.Client, canaryURL string) (bool, error) {
+	resp, err := client.Get(canaryURL)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return false, err
+	}
+	return looksBlocked(string(body), FetchOptions{}), nil
+}
+
+// recordProbe applies one checkProxy outcome to entry: success clears the
+// failure streak, records latency/egress IP, and reclassifies health state;
+// failure (including a captcha-walled canary, which counts as blocked rather
+// than merely slow) advances the streak and schedules the next check with
+// exponential backoff so a banned proxy isn't re-probed every Interval.
+func (s *ProxyService) recordProbe(entry *ProxyEntry, cfg HealthCheckConfig, result probeResult) {
+	s.mutex.Lock()
+
+	entry.LastCheckedAt = time.Now()
+	if result.captcha {
+		entry.CaptchaCount++
+	}
+
+	if result.err != nil || result.captcha {
+		entry.ConsecutiveFailures++
+	} else {
+		entry.ConsecutiveFailures = 0
+		entry.LastSuccessAt = entry.LastCheckedAt
+		entry.LastLatencyMs = result.latency.Milliseconds()
+		entry.EgressIP = result.egressIP
+	}
+
+	entry.HealthState = classifyHealth(entry.ConsecutiveFailures, cfg)
+	entry.Healthy = entry.HealthState != ProxyHealthBanned
+	entry.nextCheckAt = time.Now().Add(backoffFor(entry.ConsecutiveFailures, cfg))
+
+	analytics := s.analytics
+	id, healthy := entry.ID, entry.Healthy
+	s.mutex.Unlock()
+
+	probe := delayProbeResult{DelayMs: result.latency.Milliseconds(), At: time.Now()}
+	if result.err != nil {
+		probe.Error = result.err.Error()
+	} else if result.captcha {
+		probe.Error = "canary probe hit a CAPTCHA wall"
+	}
+	entry.recordDelayProbe(probe)
+
+	if analytics != nil {
+		analytics.SetProxyHealthy(id, healthy)
+		if result.latency > 0 {
+			analytics.ObserveProxyProbeLatency(id, result.latency)
+		}
+		if result.captcha {
+			analytics.TrackProxyCaptcha(id)
+		}
+	}
+}
+
+// classifyHealth maps a proxy's consecutive-failure streak to a health
+// state per cfg's thresholds.
+func classifyHealth(consecutiveFailures int, cfg HealthCheckConfig) ProxyHealthState {
+	switch {
+	case consecutiveFailures >= cfg.BanFailures:
+		return ProxyHealthBanned
+	case consecutiveFailures >= cfg.MaxFailures:
+		return ProxyHealthDegraded
+	default:
+		return ProxyHealthHealthy
+	}
+}
+
+// backoffFor returns how long to wait before the next check of a proxy that
+// has just failed consecutiveFailures times in a row: cfg.Interval doubled
+// once per failure, capped at cfg.MaxBackoff.
+func backoffFor(consecutiveFailures int, cfg HealthCheckConfig) time.Duration {
+	backoff := cfg.Interval
+	for i := 0; i < consecutiveFailures && backoff < cfg.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > cfg.MaxBackoff {
+		backoff = cfg.MaxBackoff
+	}
+	return backoff
+}
+
+// clientForEntry builds an *http.Client routed through entry's proxy.
+func (s *ProxyService) clientForEntry(entry *ProxyEntry, timeout time.Duration) (*http.Client, error) {
+	proxyURL := fmt.Sprintf("http://%s:%s@%s:%s", entry.Username, entry.Password, entry.Host, entry.Port)
+	parsedProxy, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyURL(parsedProxy),
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}, nil
 }
 
 // ConfigureProxy sets up proxy configuration
@@ -171,9 +836,95 @@ func (s *ProxyService) ConfigureProxy(username, password, host, port string) err
 	return nil
 }
 
-// GetClient returns the HTTP client (with or without proxy)
+// GetClient returns an HTTP client for an anonymous (non-per-request) caller,
+// routed through a weighted-round-robin pick from the persisted proxy pool
+// when one is available, falling back to the static client set by
+// ConfigureProxy (or no proxy at all) otherwise.
 func (s *ProxyService) GetClient() *http.Client {
-	return s.client
+	return s.getClient("")
+}
+
+// GetClientForMarketplace is like GetClient but restricts the weighted
+// pick to proxies whose ProxyEntry.Marketplaces includes marketplace (or
+// carry no restriction at all), so e.g. a Walmart plugin's requests never
+// land on a proxy pool reserved for Amazon. Falls back to GetClient's
+// unrestricted pool when marketplace is empty.
+func (s *ProxyService) GetClientForMarketplace(marketplace string) *http.Client {
+	return s.getClient(marketplace)
+}
+
+func (s *ProxyService) getClient(marketplace string) *http.Client {
+	s.mutex.RLock()
+	candidate := s.pickWeightedProxy(marketplace)
+	s.mutex.RUnlock()
+
+	if candidate == nil {
+		return s.client
+	}
+
+	client, err := s.clientForEntry(candidate, 30*time.Second)
+	if err != nil {
+		log.Printf("failed to build client for pooled proxy %s, falling back: %v", candidate.ID, err)
+		return s.client
+	}
+	client.Transport = &instrumentedTransport{entry: candidate, base: client.Transport, proxyService: s}
+
+	return client
+}
+
+// GetClientForKey is like GetClient but, when key is non-empty, keeps
+// retries of the same key (e.g. an ASIN) on the same proxy via the sticky
+// session map, so they reuse the same egress IP instead of bouncing between
+// pooled proxies. See stickyKeyFromURL, which the scraper's Fetcher derives
+// key from. targetHost is checked against the pool's bypass domains (see
+// SetBypassDomains); a bypassed host skips the proxy pool entirely and goes
+// out directly.
+func (s *ProxyService) GetClientForKey(key, targetHost string) *http.Client {
+	if s.isBypassed(targetHost) {
+		return &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		}
+	}
+
+	if key == "" {
+		return s.getClient("")
+	}
+
+	s.stickyMu.Lock()
+	proxyID, hasSticky := s.stickySessions[key]
+	s.stickyMu.Unlock()
+
+	s.mutex.RLock()
+	var candidate *ProxyEntry
+	if hasSticky {
+		if proxy, exists := s.proxies[proxyID]; exists && proxy.Active && proxy.Healthy && !proxy.Draining {
+			candidate = proxy
+		}
+	}
+	if candidate == nil {
+		candidate = s.pickWeightedProxy("")
+	}
+	s.mutex.RUnlock()
+
+	if candidate == nil {
+		return s.client
+	}
+
+	s.stickyMu.Lock()
+	s.stickySessions[key] = candidate.ID
+	s.stickyMu.Unlock()
+
+	client, err := s.clientForEntry(candidate, 30*time.Second)
+	if err != nil {
+		log.Printf("failed to build client for pooled proxy %s, falling back: %v", candidate.ID, err)
+		return s.client
+	}
+	client.Transport = &instrumentedTransport{entry: candidate, base: client.Transport, proxyService: s}
+
+	return client
 }
 
 // IsProxyEnabled returns whether proxy is enabled
@@ -195,41 +946,154 @@ func (s *ProxyService) GetProxyConfig() map[string]interface{} {
 // TestProxy tests the proxy connection
 func (s *ProxyService) TestProxy() *ProxyStatus {
 	if !s.config.Enabled {
+		return s.testPoolProxy()
+	}
+
+	start := time.Now()
+
+	// Test connection by making a request to a test endpoint
+	resp, err := s.client.Get("https://httpbin.org/ip")
+	latency := time.Since(start).Milliseconds()
+
+	if err != nil {
+		return &ProxyStatus{
+			Connected: false,
+			Latency:   latency,
+			Error:     err.Error(),
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
 		return &ProxyStatus{
 			Connected: false,
-			Error:     "Proxy not configured",
+			Latency:   latency,
+			Error:     fmt.Sprintf("HTTP %d", resp.StatusCode),
 		}
 	}
 
+	return &ProxyStatus{
+		Connected: true,
+		Latency:   latency,
+		IP:        "Proxy IP (hidden for security)",
+		Location:  "Proxy Location",
+	}
+}
+
+// testPoolProxy dispatches a real connectivity probe through a proxy drawn
+// from the persisted pool, for callers that never called ConfigureProxy.
+func (s *ProxyService) testPoolProxy() *ProxyStatus {
+	s.mutex.RLock()
+	candidate := s.pickWeightedProxy("")
+	cfg := s.healthCheck
+	s.mutex.RUnlock()
+
+	if candidate == nil {
+		return &ProxyStatus{Connected: false, Error: "No proxy configured"}
+	}
+
+	client, err := s.clientForEntry(candidate, cfg.Timeout)
+	if err != nil {
+		return &ProxyStatus{Connected: false, Error: err.Error()}
+	}
+
 	start := time.Now()
-	
-	// Test connection by making a request to a test endpoint
-	resp, err := s.client.Get("https://httpbin.org/ip")
+	egressIP, err := probeEgressIP(client, cfg.CheckURL)
 	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		return &ProxyStatus{Connected: false, Latency: latency, Error: err.Error()}
+	}
+
+	return &ProxyStatus{Connected: true, Latency: latency, IP: egressIP, Location: candidate.Name}
+}
+
+// defaultDelayTestURL is the target TestProxyDelay probes against when the
+// caller doesn't supply one, a Clash-style lightweight 204 endpoint.
+const defaultDelayTestURL = "http://cp.cloudflare.com/generate_204"
+
+// defaultDelayTimeout is TestProxyDelay's default per-probe timeout.
+const defaultDelayTimeout = 5 * time.Second
+
+// ProxyDelayResult is one proxy's delay-probe outcome, as returned by the
+// single and bulk delay endpoints.
+type ProxyDelayResult struct {
+	ProxyID string `json:"proxy_id"`
+	DelayMs int64  `json:"delay_ms"`
+	Status  int    `json:"status,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// TestProxyDelay performs a Clash-style delay probe: a GET against testURL
+// (defaultDelayTestURL if empty) through proxyID's upstream, measuring
+// wall-clock time to first byte. On timeout, connection failure, or a
+// non-2xx response, DelayMs is 0 and Error is set. Every outcome is cached
+// on the proxy's delay-probe ring (see ProxyEntry.recordDelayProbe) so
+// ListProxies can surface last_delay_ms/last_checked_at without re-probing.
+func (s *ProxyService) TestProxyDelay(proxyID, testURL string, timeout time.Duration) (*ProxyDelayResult, error) {
+	s.mutex.RLock()
+	entry, exists := s.proxies[proxyID]
+	s.mutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("proxy not found")
+	}
+
+	if testURL == "" {
+		testURL = defaultDelayTestURL
+	}
+	if timeout <= 0 {
+		timeout = defaultDelayTimeout
+	}
+
+	client, err := s.clientForEntry(entry, timeout)
+	if err != nil {
+		entry.recordDelayProbe(delayProbeResult{Error: err.Error(), At: time.Now()})
+		return &ProxyDelayResult{ProxyID: proxyID, Error: err.Error()}, nil
+	}
 
+	start := time.Now()
+	resp, err := client.Get(testURL)
+	elapsed := time.Since(start).Milliseconds()
 	if err != nil {
-		return &ProxyStatus{
-			Connected: false,
-			Latency:   latency,
-			Error:     err.Error(),
-		}
+		entry.recordDelayProbe(delayProbeResult{Error: err.Error(), At: time.Now()})
+		return &ProxyDelayResult{ProxyID: proxyID, Error: err.Error()}, nil
 	}
 	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		return &ProxyStatus{
-			Connected: false,
-			Latency:   latency,
-			Error:     fmt.Sprintf("HTTP %d", resp.StatusCode),
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		errMsg := fmt.Sprintf("HTTP %d", resp.StatusCode)
+		entry.recordDelayProbe(delayProbeResult{Status: resp.StatusCode, Error: errMsg, At: time.Now()})
+		return &ProxyDelayResult{ProxyID: proxyID, Status: resp.StatusCode, Error: errMsg}, nil
+	}
+
+	entry.recordDelayProbe(delayProbeResult{DelayMs: elapsed, Status: resp.StatusCode, At: time.Now()})
+	return &ProxyDelayResult{ProxyID: proxyID, DelayMs: elapsed, Status: resp.StatusCode}, nil
+}
+
+// TestProxyDelayBulk runs TestProxyDelay against every ID in proxyIDs,
+// skipping (rather than failing the whole batch on) any ID that doesn't
+// exist.
+func (s *ProxyService) TestProxyDelayBulk(proxyIDs []string, testURL string, timeout time.Duration) []*ProxyDelayResult {
+	results := make([]*ProxyDelayResult, 0, len(proxyIDs))
+	for _, proxyID := range proxyIDs {
+		result, err := s.TestProxyDelay(proxyID, testURL, timeout)
+		if err != nil {
+			continue
 		}
+		results = append(results, result)
 	}
+	return results
+}
 
-	return &ProxyStatus{
-		Connected: true,
-		Latency:   latency,
-		IP:        "Proxy IP (hidden for security)",
-		Location:  "Proxy Location",
+// proxyDisplayDelay returns entry's last_delay_ms/last_checked_at for
+// ProxyResponse: its most recently cached delay probe if one is still
+// within delayCacheTTL, else the background health checker's own latency
+// fields.
+func proxyDisplayDelay(entry *ProxyEntry) (int64, time.Time) {
+	if result, ok := entry.latestDelay(); ok {
+		return result.DelayMs, result.At
 	}
+	return entry.LastLatencyMs, entry.LastCheckedAt
 }
 
 // DisableProxy disables proxy usage
@@ -249,95 +1113,91 @@ func (s *ProxyService) DisableProxy() {
 
 // CreateProxy creates a new proxy configuration
 func (s *ProxyService) CreateProxy(req CreateProxyRequest, createdBy string) (*ProxyResponse, error) {
+	matcher, err := newBypassMatcher(req.NoProxy)
+	if err != nil {
+		return nil, err
+	}
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	
+
 	// Create new proxy
 	proxy := &ProxyEntry{
-		ID:        uuid.New().String(),
-		Name:      req.Name,
-		Username:  req.Username,
-		Password:  req.Password,
-		Host:      req.Host,
-		Port:      req.Port,
-		Active:    req.Active,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-		CreatedBy: createdBy,
+		ID:              uuid.New().String(),
+		Name:            req.Name,
+		Username:        req.Username,
+		Password:        req.Password,
+		Host:            req.Host,
+		Port:            req.Port,
+		Active:          req.Active,
+		Marketplaces:    req.Marketplaces,
+		NoProxy:         req.NoProxy,
+		Healthy:         true, // assumed healthy until the background checker says otherwise
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+		CreatedBy:       createdBy,
+		concurrentLimit: make(chan struct{}, defaultConcurrentLimit),
+		noProxyMatcher:  matcher,
 	}
-	
+
 	s.proxies[proxy.ID] = proxy
-	
-	return &ProxyResponse{
-		ID:        proxy.ID,
-		Name:      proxy.Name,
-		Username:  proxy.Username,
-		Host:      proxy.Host,
-		Port:      proxy.Port,
-		Active:    proxy.Active,
-		CreatedAt: proxy.CreatedAt,
-		UpdatedAt: proxy.UpdatedAt,
-		CreatedBy: proxy.CreatedBy,
-	}, nil
+
+	if s.store != nil {
+		if err := s.store.Save(proxy); err != nil {
+			log.Printf("failed to persist proxy %s: %v", proxy.ID, err)
+		}
+	}
+	s.notifyChanged()
+
+	return proxyToResponse(proxy), nil
 }
 
 // GetProxy gets a proxy by ID
 func (s *ProxyService) GetProxy(proxyID string) (*ProxyResponse, error) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	
+
 	proxy, exists := s.proxies[proxyID]
 	if !exists {
 		return nil, fmt.Errorf("proxy not found")
 	}
-	
-	return &ProxyResponse{
-		ID:        proxy.ID,
-		Name:      proxy.Name,
-		Username:  proxy.Username,
-		Host:      proxy.Host,
-		Port:      proxy.Port,
-		Active:    proxy.Active,
-		CreatedAt: proxy.CreatedAt,
-		UpdatedAt: proxy.UpdatedAt,
-		CreatedBy: proxy.CreatedBy,
-	}, nil
+
+	return proxyToResponse(proxy), nil
 }
 
 // ListProxies lists all proxy configurations
 func (s *ProxyService) ListProxies() []*ProxyResponse {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	
+
 	var proxies []*ProxyResponse
-	
+
 	for _, proxy := range s.proxies {
-		proxies = append(proxies, &ProxyResponse{
-			ID:        proxy.ID,
-			Name:      proxy.Name,
-			Username:  proxy.Username,
-			Host:      proxy.Host,
-			Port:      proxy.Port,
-			Active:    proxy.Active,
-			CreatedAt: proxy.CreatedAt,
-			UpdatedAt: proxy.UpdatedAt,
-			CreatedBy: proxy.CreatedBy,
-		})
+		proxies = append(proxies, proxyToResponse(proxy))
 	}
-	
+
 	return proxies
 }
 
 // UpdateProxy updates an existing proxy
 func (s *ProxyService) UpdateProxy(proxyID string, req UpdateProxyRequest) (*ProxyResponse, error) {
+	var matcher *bypassMatcher
+	if req.NoProxy != "" {
+		var err error
+		matcher, err = newBypassMatcher(req.NoProxy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	
+
 	proxy, exists := s.proxies[proxyID]
 	if !exists {
 		return nil, fmt.Errorf("proxy not found")
 	}
-	
+
 	// Update fields if provided
 	if req.Name != "" {
 		proxy.Name = req.Name
@@ -357,117 +1217,198 @@ func (s *ProxyService) UpdateProxy(proxyID string, req UpdateProxyRequest) (*Pro
 	if req.Active != nil {
 		proxy.Active = *req.Active
 	}
-	
+	if req.Marketplaces != nil {
+		proxy.Marketplaces = req.Marketplaces
+	}
+	if req.NoProxy != "" {
+		proxy.NoProxy = req.NoProxy
+		proxy.noProxyMatcher = matcher
+	}
+
 	proxy.UpdatedAt = time.Now()
-	
-	return &ProxyResponse{
-		ID:        proxy.ID,
-		Name:      proxy.Name,
-		Username:  proxy.Username,
-		Host:      proxy.Host,
-		Port:      proxy.Port,
-		Active:    proxy.Active,
-		CreatedAt: proxy.CreatedAt,
-		UpdatedAt: proxy.UpdatedAt,
-		CreatedBy: proxy.CreatedBy,
-	}, nil
+
+	if s.store != nil {
+		if err := s.store.Save(proxy); err != nil {
+			log.Printf("failed to persist proxy %s: %v", proxy.ID, err)
+		}
+	}
+	s.notifyChanged()
+
+	return proxyToResponse(proxy), nil
 }
 
 // DeleteProxy deletes a proxy configuration
 func (s *ProxyService) DeleteProxy(proxyID string) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	
-	if _, exists := s.proxies[proxyID]; !exists {
+
+	entry, exists := s.proxies[proxyID]
+	if !exists {
 		return fmt.Errorf("proxy not found")
 	}
-	
+
 	// Don't allow deleting default proxy
 	if proxyID == s.defaultProxyID {
 		return fmt.Errorf("cannot delete default proxy")
 	}
-	
+
+	// Config-file-provisioned proxies are owned by proxies.yaml; removing one
+	// from the file (and reloading) is the supported way to drop it.
+	if entry.FromFile {
+		return fmt.Errorf("cannot delete a proxy managed by the proxies config file")
+	}
+
 	// Remove proxy assignments
 	for username, assignedProxyID := range s.userProxies {
 		if assignedProxyID == proxyID {
 			delete(s.userProxies, username)
 		}
 	}
-	
+
 	delete(s.proxies, proxyID)
+
+	if s.store != nil {
+		if err := s.store.Delete(proxyID); err != nil {
+			log.Printf("failed to delete persisted proxy %s: %v", proxyID, err)
+		}
+	}
+	s.notifyChanged()
+
 	return nil
 }
 
-// AssignProxyToUser assigns a proxy to a user
-func (s *ProxyService) AssignProxyToUser(username, proxyID string) error {
+// AssignProxyToUser assigns a proxy or a proxy group to a user. Exactly one
+// of proxyID or groupID must be non-empty; whichever is set replaces any
+// existing assignment of either kind for username. noProxy, if non-empty,
+// overrides the assigned proxy's own NoProxy bypass list for this user only
+// (see ResolveUserProxy); an empty noProxy clears any previous override.
+func (s *ProxyService) AssignProxyToUser(username, proxyID, groupID, noProxy string) error {
+	if (proxyID == "") == (groupID == "") {
+		return fmt.Errorf("exactly one of proxy_id or group_id must be set")
+	}
+
+	matcher, err := newBypassMatcher(noProxy)
+	if err != nil {
+		return err
+	}
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	
+
+	if groupID != "" {
+		if _, exists := s.proxyGroups[groupID]; !exists {
+			return fmt.Errorf("proxy group not found")
+		}
+		s.userGroups[username] = groupID
+		delete(s.userProxies, username)
+		s.setUserBypass(username, matcher)
+		s.reportAssignmentCount()
+		s.notifyChanged()
+		return nil
+	}
+
 	// Check if proxy exists and is active
 	proxy, exists := s.proxies[proxyID]
 	if !exists {
 		return fmt.Errorf("proxy not found")
 	}
-	
+
 	if !proxy.Active {
 		return fmt.Errorf("proxy is not active")
 	}
-	
+	if !proxy.Healthy {
+		return fmt.Errorf("proxy has failed its health checks and cannot be assigned")
+	}
+
 	s.userProxies[username] = proxyID
+	delete(s.userGroups, username)
+	s.setUserBypass(username, matcher)
+	s.reportAssignmentCount()
+	s.notifyChanged()
 	return nil
 }
 
-// UnassignProxyFromUser removes proxy assignment from user
+// setUserBypass records (or clears, if matcher is nil) username's per-
+// assignment no_proxy override. Callers must hold s.mutex.
+func (s *ProxyService) setUserBypass(username string, matcher *bypassMatcher) {
+	if matcher == nil {
+		delete(s.userBypass, username)
+		return
+	}
+	s.userBypass[username] = matcher
+}
+
+// UnassignProxyFromUser removes any proxy or proxy-group assignment from user
 func (s *ProxyService) UnassignProxyFromUser(username string) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	
+
 	delete(s.userProxies, username)
+	delete(s.userGroups, username)
+	delete(s.userBypass, username)
+	s.reportAssignmentCount()
 	return nil
 }
 
-// GetUserProxy gets the assigned proxy for a user
+// GetUserProxy gets the assigned proxy for a user, resolving a group
+// assignment to its current member if the user was assigned one (see
+// resolveUserProxy).
 func (s *ProxyService) GetUserProxy(username string) (*ProxyResponse, error) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	
-	proxyID, exists := s.userProxies[username]
-	if !exists {
-		// Return default proxy if no specific assignment
-		proxyID = s.defaultProxyID
+
+	proxy, err := s.resolveUserProxy(username)
+	if err != nil {
+		return nil, err
 	}
-	
-	proxy, exists := s.proxies[proxyID]
-	if !exists {
-		return nil, fmt.Errorf("assigned proxy not found")
+
+	return proxyToResponse(proxy), nil
+}
+
+// ProxyResolution is the result of asking ResolveUserProxy whether a target
+// URL should traverse a user's assigned proxy.
+type ProxyResolution struct {
+	ProxyID string `json:"proxy_id"`
+	Bypass  bool   `json:"bypass"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// ResolveUserProxy reports whether targetURL should bypass username's
+// assigned proxy, checking the per-assignment no_proxy override (see
+// AssignProxyToUser) before the assigned proxy's own NoProxy list.
+func (s *ProxyService) ResolveUserProxy(username, targetURL string) (*ProxyResolution, error) {
+	parsed, err := url.Parse(targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target url: %w", err)
 	}
-	
-	return &ProxyResponse{
-		ID:        proxy.ID,
-		Name:      proxy.Name,
-		Username:  proxy.Username,
-		Host:      proxy.Host,
-		Port:      proxy.Port,
-		Active:    proxy.Active,
-		CreatedAt: proxy.CreatedAt,
-		UpdatedAt: proxy.UpdatedAt,
-		CreatedBy: proxy.CreatedBy,
-	}, nil
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	proxy, err := s.resolveUserProxy(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if matcher := s.userBypass[username]; matcher.bypasses(parsed) {
+		return &ProxyResolution{ProxyID: proxy.ID, Bypass: true, Reason: "matched assignment's no_proxy rule"}, nil
+	}
+	if proxy.noProxyMatcher.bypasses(parsed) {
+		return &ProxyResolution{ProxyID: proxy.ID, Bypass: true, Reason: "matched proxy's no_proxy rule"}, nil
+	}
+
+	return &ProxyResolution{ProxyID: proxy.ID}, nil
 }
 
-// GetClientForUser returns HTTP client configured with user's assigned proxy
+// GetClientForUser returns HTTP client configured with user's assigned
+// proxy or proxy group (see resolveUserProxy)
 func (s *ProxyService) GetClientForUser(username string) *http.Client {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	
-	proxyID, exists := s.userProxies[username]
-	if !exists {
-		proxyID = s.defaultProxyID
-	}
-	
-	proxy, exists := s.proxies[proxyID]
-	if !exists || !proxy.Active {
-		// Return default client if no proxy or proxy inactive
+
+	proxy, err := s.resolveUserProxy(username)
+	if err != nil || !proxy.Active {
+		// Return default client if no proxy, no healthy group member, or proxy inactive
 		return &http.Client{
 			Timeout: 30 * time.Second,
 			Transport: &http.Transport{
@@ -477,10 +1418,10 @@ func (s *ProxyService) GetClientForUser(username string) *http.Client {
 			},
 		}
 	}
-	
+
 	// Create proxy URL
 	proxyURL := fmt.Sprintf("http://%s:%s@%s:%s", proxy.Username, proxy.Password, proxy.Host, proxy.Port)
-	
+
 	// Parse proxy URL
 	parsedProxy, err := url.Parse(proxyURL)
 	if err != nil {
@@ -494,41 +1435,362 @@ func (s *ProxyService) GetClientForUser(username string) *http.Client {
 			},
 		}
 	}
-	
-	// Return client with proxy
+
+	// Return client with proxy, wrapped so bytes/latency are attributed to proxy.ID
 	return &http.Client{
 		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			Proxy: http.ProxyURL(parsedProxy),
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
+		Transport: &instrumentedTransport{
+			entry: proxy,
+			base: &http.Transport{
+				Proxy: http.ProxyURL(parsedProxy),
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true,
+				},
 			},
+			proxyService: s,
+		},
+	}
+}
+
+// proxyToResponse builds the API representation of proxy, including its
+// current delay reading. Callers must hold at least s.mutex.RLock.
+func proxyToResponse(proxy *ProxyEntry) *ProxyResponse {
+	delayMs, checkedAt := proxyDisplayDelay(proxy)
+	return &ProxyResponse{
+		ID:            proxy.ID,
+		Name:          proxy.Name,
+		Username:      proxy.Username,
+		Host:          proxy.Host,
+		Port:          proxy.Port,
+		Active:        proxy.Active,
+		CreatedAt:     proxy.CreatedAt,
+		UpdatedAt:     proxy.UpdatedAt,
+		CreatedBy:     proxy.CreatedBy,
+		Marketplaces:  proxy.Marketplaces,
+		LastDelayMs:   delayMs,
+		LastCheckedAt: checkedAt,
+		FromFile:      proxy.FromFile,
+		NoProxy:       proxy.NoProxy,
+	}
+}
+
+// pickWeightedProxy selects an eligible proxy using weighted round-robin,
+// where weight is success_rate / latency (so a proxy that's fast but
+// frequently failing doesn't crowd out a slower, more reliable one).
+// Degraded proxies stay eligible but are heavily downweighted; banned or
+// draining ones are excluded entirely. When marketplace is non-empty, only
+// proxies with no Marketplaces restriction or one that includes it are
+// eligible. Callers must hold at least s.mutex.RLock().
+func (s *ProxyService) pickWeightedProxy(marketplace string) *ProxyEntry {
+	type weighted struct {
+		entry  *ProxyEntry
+		weight float64
+	}
+
+	var candidates []weighted
+	var totalWeight float64
+
+	for _, proxy := range s.proxies {
+		if !proxy.Active || !proxy.Healthy || proxy.Draining {
+			continue
+		}
+		if marketplace != "" && !proxyServesMarketplace(proxy, marketplace) {
+			continue
+		}
+
+		latency := float64(proxy.LastLatencyMs)
+		if latency <= 0 {
+			latency = 1 // untested proxies get a high but finite weight
+		}
+
+		successRate := 1.0 // untested proxies default to a neutral rate so they still get picked
+		successes := atomic.LoadInt64(&proxy.Stats.SuccessCount)
+		failures := atomic.LoadInt64(&proxy.Stats.FailureCount)
+		if total := successes + failures; total > 0 {
+			successRate = float64(successes) / float64(total)
+			if successRate <= 0 {
+				successRate = 0.01 // keep a non-zero floor so a rough patch doesn't permanently zero a proxy's weight
+			}
+		}
+
+		degradeFactor := 1.0
+		if proxy.HealthState == ProxyHealthDegraded {
+			degradeFactor = 0.1
+		}
+
+		weight := 1000.0 * successRate * degradeFactor / latency
+		candidates = append(candidates, weighted{entry: proxy, weight: weight})
+		totalWeight += weight
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// Deterministic weighted round-robin: advance a monotonic cursor through
+	// the weighted space so proxies are chosen proportionally to their weight.
+	cursor := atomic.AddUint64(&s.rrCounter, 1)
+	target := float64(cursor%1000) / 1000.0 * totalWeight
+
+	var acc float64
+	for _, c := range candidates {
+		acc += c.weight
+		if target <= acc {
+			return c.entry
+		}
+	}
+
+	return candidates[len(candidates)-1].entry
+}
+
+// proxyServesMarketplace reports whether proxy is eligible for marketplace:
+// true if the proxy carries no Marketplaces restriction at all, or if
+// marketplace is one of the ones it lists.
+func proxyServesMarketplace(proxy *ProxyEntry, marketplace string) bool {
+	if len(proxy.Marketplaces) == 0 {
+		return true
+	}
+	for _, m := range proxy.Marketplaces {
+		if m == marketplace {
+			return true
+		}
+	}
+	return false
+}
+
+// ProxyDashboardEntry is a proxy's configuration plus its live health and
+// traffic counters, as shown on the proxy status dashboard.
+type ProxyDashboardEntry struct {
+	ProxyResponse
+	Healthy             bool             `json:"healthy"`
+	HealthState         ProxyHealthState `json:"health_state,omitempty"`
+	Draining            bool             `json:"draining"`
+	EgressIP            string           `json:"egress_ip,omitempty"`
+	LastCheckedAt       time.Time        `json:"last_checked_at,omitempty"`
+	LastLatencyMs       int64            `json:"last_latency_ms"`
+	ConsecutiveFailures int              `json:"consecutive_failures"`
+	Stats               ProxyStatistics  `json:"stats"`
+}
+
+// TrafficPoint is one per-minute sample in a proxy's traffic-over-time series.
+type TrafficPoint struct {
+	Minute         int64   `json:"minute"` // unix minute (minute*60 = unix seconds)
+	Requests       int64   `json:"requests"`
+	TrafficIn      int64   `json:"traffic_in_bytes"`
+	TrafficOut     int64   `json:"traffic_out_bytes"`
+	AvgLatencyMs   float64 `json:"avg_latency_ms"`
+}
+
+// dashboardEntry builds a ProxyDashboardEntry for entry. Callers must hold
+// at least s.mutex.RLock().
+func dashboardEntry(entry *ProxyEntry) *ProxyDashboardEntry {
+	delayMs, _ := proxyDisplayDelay(entry)
+	return &ProxyDashboardEntry{
+		ProxyResponse: ProxyResponse{
+			ID:           entry.ID,
+			Name:         entry.Name,
+			Username:     entry.Username,
+			Host:         entry.Host,
+			Port:         entry.Port,
+			Active:       entry.Active,
+			CreatedAt:    entry.CreatedAt,
+			UpdatedAt:    entry.UpdatedAt,
+			CreatedBy:    entry.CreatedBy,
+			Marketplaces: entry.Marketplaces,
+			LastDelayMs:  delayMs,
+			FromFile:     entry.FromFile,
+		},
+		Healthy:             entry.Healthy,
+		HealthState:         entry.HealthState,
+		Draining:            entry.Draining,
+		EgressIP:            entry.EgressIP,
+		LastCheckedAt:       entry.LastCheckedAt,
+		LastLatencyMs:       entry.LastLatencyMs,
+		ConsecutiveFailures: entry.ConsecutiveFailures,
+		Stats: ProxyStatistics{
+			TrafficIn:    atomic.LoadInt64(&entry.Stats.TrafficIn),
+			TrafficOut:   atomic.LoadInt64(&entry.Stats.TrafficOut),
+			CurConns:     atomic.LoadInt64(&entry.Stats.CurConns),
+			SuccessCount: atomic.LoadInt64(&entry.Stats.SuccessCount),
+			FailureCount: atomic.LoadInt64(&entry.Stats.FailureCount),
+			LastUsed:     entry.Stats.LastUsedAt(),
 		},
 	}
 }
 
-// ListUserProxyAssignments lists all user-proxy assignments
-func (s *ProxyService) ListUserProxyAssignments() map[string]*ProxyResponse {
+// GetProxyDashboard returns proxyID's configuration, health and traffic
+// stats for the dashboard's proxy-detail view.
+func (s *ProxyService) GetProxyDashboard(proxyID string) (*ProxyDashboardEntry, error) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	
-	assignments := make(map[string]*ProxyResponse)
-	
-	for username, proxyID := range s.userProxies {
-		if proxy, exists := s.proxies[proxyID]; exists {
-			assignments[username] = &ProxyResponse{
-				ID:        proxy.ID,
-				Name:      proxy.Name,
-				Username:  proxy.Username,
-				Host:      proxy.Host,
-				Port:      proxy.Port,
-				Active:    proxy.Active,
-				CreatedAt: proxy.CreatedAt,
-				UpdatedAt: proxy.UpdatedAt,
-				CreatedBy: proxy.CreatedBy,
-			}
+
+	entry, exists := s.proxies[proxyID]
+	if !exists {
+		return nil, fmt.Errorf("proxy not found")
+	}
+	return dashboardEntry(entry), nil
+}
+
+// ListProxyDashboard returns every proxy's configuration, health and traffic
+// stats for the dashboard's overview list.
+func (s *ProxyService) ListProxyDashboard() []*ProxyDashboardEntry {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entries := make([]*ProxyDashboardEntry, 0, len(s.proxies))
+	for _, entry := range s.proxies {
+		entries = append(entries, dashboardEntry(entry))
+	}
+	return entries
+}
+
+// GetProxyTraffic returns proxyID's per-minute traffic history for the last
+// windowMinutes minutes (clamped to ringSlots), oldest first.
+func (s *ProxyService) GetProxyTraffic(proxyID string, windowMinutes int) ([]TrafficPoint, error) {
+	s.mutex.RLock()
+	entry, exists := s.proxies[proxyID]
+	s.mutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("proxy not found")
+	}
+
+	if windowMinutes <= 0 {
+		windowMinutes = 60
+	}
+	if windowMinutes > ringSlots {
+		windowMinutes = ringSlots
+	}
+
+	now := time.Now().Unix() / 60
+
+	entry.trafficMu.Lock()
+	defer entry.trafficMu.Unlock()
+
+	points := make([]TrafficPoint, 0, windowMinutes)
+	for i := windowMinutes - 1; i >= 0; i-- {
+		minute := now - int64(i)
+		slot := entry.trafficBuckets[minute%ringSlots]
+		if slot.minute != minute {
+			points = append(points, TrafficPoint{Minute: minute})
+			continue
+		}
+
+		avgLatency := 0.0
+		if slot.requests > 0 {
+			avgLatency = slot.totalLatencyMs / float64(slot.requests)
 		}
+		points = append(points, TrafficPoint{
+			Minute:       minute,
+			Requests:     slot.requests,
+			TrafficIn:    slot.trafficIn,
+			TrafficOut:   slot.trafficOut,
+			AvgLatencyMs: avgLatency,
+		})
 	}
-	
-	return assignments
-} 
\ No newline at end of file
+
+	return points, nil
+}
+
+// DrainProxy marks proxyID as draining: pickWeightedProxy and the sticky
+// pickers stop selecting it for new requests, but requests already holding
+// a client (and the background health checker) are left alone to finish.
+func (s *ProxyService) DrainProxy(proxyID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, exists := s.proxies[proxyID]
+	if !exists {
+		return fmt.Errorf("proxy not found")
+	}
+
+	entry.Draining = true
+	return nil
+}
+
+// ProxyHealthReport is proxyID's rolling-window health picture, returned by
+// GET /api/v1/proxies/{proxy_id}/health.
+type ProxyHealthReport struct {
+	ProxyID             string           `json:"proxy_id"`
+	HealthState         ProxyHealthState `json:"health_state"`
+	Draining            bool             `json:"draining"`
+	EgressIP            string           `json:"egress_ip,omitempty"`
+	LastCheckedAt       time.Time        `json:"last_checked_at,omitempty"`
+	LastSuccessAt       time.Time        `json:"last_success_at,omitempty"`
+	ConsecutiveFailures int              `json:"consecutive_failures"`
+	SuccessRate         float64          `json:"success_rate"`
+	CaptchaCount        int64            `json:"captcha_count"`
+	RecentAvgLatencyMs  float64          `json:"recent_avg_latency_ms"`
+	Stats               ProxyStatistics  `json:"stats"`
+}
+
+// GetProxyHealth returns proxyID's current health state plus a rolling
+// window (default 5, clamped to ringSlots minutes) of its real traffic:
+// success rate, captcha count, and average latency.
+func (s *ProxyService) GetProxyHealth(proxyID string, windowMinutes int) (*ProxyHealthReport, error) {
+	if windowMinutes <= 0 {
+		windowMinutes = 5
+	}
+
+	points, err := s.GetProxyTraffic(proxyID, windowMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	var windowRequests int64
+	var windowLatency float64
+	for _, p := range points {
+		windowRequests += p.Requests
+		windowLatency += p.AvgLatencyMs * float64(p.Requests)
+	}
+	recentAvgLatency := 0.0
+	if windowRequests > 0 {
+		recentAvgLatency = windowLatency / float64(windowRequests)
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entry, exists := s.proxies[proxyID]
+	if !exists {
+		return nil, fmt.Errorf("proxy not found")
+	}
+
+	successes := atomic.LoadInt64(&entry.Stats.SuccessCount)
+	failures := atomic.LoadInt64(&entry.Stats.FailureCount)
+	successRate := 1.0
+	if total := successes + failures; total > 0 {
+		successRate = float64(successes) / float64(total)
+	}
+
+	return &ProxyHealthReport{
+		ProxyID:             entry.ID,
+		HealthState:         entry.HealthState,
+		Draining:            entry.Draining,
+		EgressIP:            entry.EgressIP,
+		LastCheckedAt:       entry.LastCheckedAt,
+		LastSuccessAt:       entry.LastSuccessAt,
+		ConsecutiveFailures: entry.ConsecutiveFailures,
+		SuccessRate:         successRate,
+		CaptchaCount:        entry.CaptchaCount,
+		RecentAvgLatencyMs:  recentAvgLatency,
+		Stats: ProxyStatistics{
+			TrafficIn:    atomic.LoadInt64(&entry.Stats.TrafficIn),
+			TrafficOut:   atomic.LoadInt64(&entry.Stats.TrafficOut),
+			CurConns:     atomic.LoadInt64(&entry.Stats.CurConns),
+			SuccessCount: successes,
+			FailureCount: failures,
+			LastUsed:     entry.Stats.LastUsedAt(),
+		},
+	}, nil
+}
+
+// stickyKeyFromURL extracts a stable per-product key (currently just the
+// Amazon ASIN) from rawURL so GetClientForKey can keep retries of the same
+// product on the same egress IP. URLs with no recognizable identifier get
+// no stickiness.
+func stickyKeyFromURL(rawURL string) string {
+	if matches := amazonASINFromURL.FindStringSubmatch(rawURL); matches != nil {
+		return matches[1]
+	}
+	return ""
+}
\ No newline at end of file
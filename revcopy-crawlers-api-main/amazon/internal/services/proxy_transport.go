@@ -0,0 +1,99 @@
+package services
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// instrumentedTransport wraps a base http.RoundTripper and attributes every
+// request's byte counts, latency and outcome back to entry's Stats and
+// trafficBuckets, so the dashboard endpoints can report live per-proxy
+// traffic without threading accounting through every call site. It also
+// enforces entry's concurrentLimit and reports CurConns as the proxy's
+// in-flight count, so every caller that dispatches through this entry (via
+// clientForEntry) gets both for free instead of having to opt in.
+type instrumentedTransport struct {
+	entry        *ProxyEntry
+	base         http.RoundTripper
+	proxyService *ProxyService
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBytes := approxRequestSize(req)
+
+	select {
+	case t.entry.concurrentLimit <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-t.entry.concurrentLimit }()
+
+	curConns := atomic.AddInt64(&t.entry.Stats.CurConns, 1)
+	t.proxyService.reportInFlight(t.entry, curConns)
+	defer func() {
+		curConns := atomic.AddInt64(&t.entry.Stats.CurConns, -1)
+		t.proxyService.reportInFlight(t.entry, curConns)
+	}()
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start)
+
+	if err != nil {
+		atomic.AddInt64(&t.entry.Stats.FailureCount, 1)
+		t.record(0, reqBytes, latency)
+		return nil, err
+	}
+
+	respBytes := resp.ContentLength
+	if respBytes < 0 {
+		respBytes = 0
+	}
+
+	atomic.AddInt64(&t.entry.Stats.SuccessCount, 1)
+	atomic.AddInt64(&t.entry.Stats.TrafficIn, respBytes)
+	atomic.AddInt64(&t.entry.Stats.TrafficOut, reqBytes)
+	t.entry.Stats.RecordUse(time.Now())
+	t.record(respBytes, reqBytes, latency)
+
+	return resp, nil
+}
+
+// record rolls one request's traffic/latency (in = bytes received/
+// downloaded, out = bytes sent/uploaded, matching ProxyStatistics.TrafficIn/
+// TrafficOut) into the entry's current per-minute bucket, resetting the
+// slot if it has gone stale.
+func (t *instrumentedTransport) record(in, out int64, latency time.Duration) {
+	if out < 0 {
+		out = 0
+	}
+
+	minute := time.Now().Unix() / 60
+	t.entry.trafficMu.Lock()
+	defer t.entry.trafficMu.Unlock()
+
+	slot := &t.entry.trafficBuckets[minute%ringSlots]
+	if slot.minute != minute {
+		*slot = proxyTrafficBucket{minute: minute}
+	}
+	slot.requests++
+	slot.trafficIn += in
+	slot.trafficOut += out
+	slot.totalLatencyMs += float64(latency.Milliseconds())
+}
+
+// approxRequestSize estimates the wire size of req's method/URL/headers,
+// since the body is consumed by the underlying transport rather than here.
+func approxRequestSize(req *http.Request) int64 {
+	size := int64(len(req.Method) + len(req.URL.String()) + 12)
+	for k, values := range req.Header {
+		for _, v := range values {
+			size += int64(len(k) + len(v) + 4)
+		}
+	}
+	if req.ContentLength > 0 {
+		size += req.ContentLength
+	}
+	return size
+}
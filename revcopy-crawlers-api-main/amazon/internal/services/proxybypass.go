@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// bypassPlaceholderProxy is a syntactically valid but unroutable proxy URL
+// used to compile an httpproxy.Config purely for its NoProxy matching:
+// ProxyFunc returns this URL (never actually dialed) for a non-bypassed
+// request, and nil for a bypassed one.
+const bypassPlaceholderProxy = "http://127.0.0.1:1"
+
+// bypassMatcher answers "does this target skip the proxy" for one no_proxy
+// pattern list, reusing httpproxy.Config's matching semantics (suffix match
+// with leading-dot rules, case-insensitive host compare, CIDR containment
+// for IP literals) instead of re-implementing them.
+type bypassMatcher struct {
+	raw    string
+	config *httpproxy.Config
+}
+
+// parseNoProxyList validates a comma-separated no_proxy pattern list,
+// returning an error naming the first token that isn't a well-formed
+// hostname, domain suffix, or CIDR.
+func parseNoProxyList(raw string) error {
+	for _, token := range strings.Split(raw, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		if strings.Contains(token, "/") {
+			if _, _, err := net.ParseCIDR(token); err != nil {
+				return fmt.Errorf("invalid no_proxy entry %q: not a valid CIDR", token)
+			}
+			continue
+		}
+		host := strings.TrimPrefix(token, ".")
+		if host == "" || strings.ContainsAny(host, " \t\\") {
+			return fmt.Errorf("invalid no_proxy entry %q: not a valid hostname", token)
+		}
+	}
+	return nil
+}
+
+// newBypassMatcher validates and compiles raw, returning (nil, nil) for an
+// empty list.
+func newBypassMatcher(raw string) (*bypassMatcher, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if err := parseNoProxyList(raw); err != nil {
+		return nil, err
+	}
+	return &bypassMatcher{
+		raw: raw,
+		config: &httpproxy.Config{
+			HTTPProxy:  bypassPlaceholderProxy,
+			HTTPSProxy: bypassPlaceholderProxy,
+			NoProxy:    raw,
+		},
+	}, nil
+}
+
+// bypasses reports whether targetURL matches m's no_proxy list. A nil
+// receiver (no list configured) never bypasses.
+func (m *bypassMatcher) bypasses(targetURL *url.URL) bool {
+	if m == nil {
+		return false
+	}
+	proxyURL, err := m.config.ProxyFunc()(targetURL)
+	return err == nil && proxyURL == nil
+}
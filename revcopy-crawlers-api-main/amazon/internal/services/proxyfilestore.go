@@ -0,0 +1,104 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// JSONFileProxyStore is a ProxyStore that keeps one plain JSON file per
+// proxy under dir, named <id>.json. It's a simpler alternative to
+// BoltProxyStore for deployments that don't need (or don't want to manage
+// the key for) encryption at rest -- API-created proxy passwords are
+// written in the clear, so dir must be protected at the filesystem level.
+type JSONFileProxyStore struct {
+	dir string
+}
+
+// NewJSONFileProxyStore returns a store that reads/writes proxy files under
+// dir, creating it if necessary.
+func NewJSONFileProxyStore(dir string) (*JSONFileProxyStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create proxy store directory: %w", err)
+	}
+	return &JSONFileProxyStore{dir: dir}, nil
+}
+
+// path returns the on-disk path for a proxy's file, given its ID.
+func (f *JSONFileProxyStore) path(id string) string {
+	return filepath.Join(f.dir, id+".json")
+}
+
+// jsonStoredProxyEntry is the on-disk representation of a ProxyEntry in a
+// JSONFileProxyStore file. ProxyEntry.Password is tagged json:"-" so the API
+// never echoes it back; PlaintextPassword is this store's only copy.
+type jsonStoredProxyEntry struct {
+	ProxyEntry
+	PlaintextPassword string `json:"plaintext_password"`
+}
+
+// Load returns every persisted proxy.
+func (f *JSONFileProxyStore) Load() ([]*ProxyEntry, error) {
+	return f.List()
+}
+
+// List returns every persisted proxy, stopping at (and returning) the first
+// file that doesn't parse as a jsonStoredProxyEntry.
+func (f *JSONFileProxyStore) List() ([]*ProxyEntry, error) {
+	files, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list proxy store directory: %w", err)
+	}
+
+	var entries []*ProxyEntry
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(f.dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Name(), err)
+		}
+
+		var stored jsonStoredProxyEntry
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", file.Name(), err)
+		}
+
+		entry := stored.ProxyEntry
+		entry.Password = stored.PlaintextPassword
+		entry.concurrentLimit = make(chan struct{}, defaultConcurrentLimit)
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+// Save writes entry to its file, overwriting any previous contents.
+func (f *JSONFileProxyStore) Save(entry *ProxyEntry) error {
+	copyEntry := *entry
+	copyEntry.concurrentLimit = nil
+
+	stored := jsonStoredProxyEntry{ProxyEntry: copyEntry, PlaintextPassword: entry.Password}
+	data, err := json.MarshalIndent(&stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode proxy %s: %w", entry.ID, err)
+	}
+
+	if err := os.WriteFile(f.path(entry.ID), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write proxy %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+// Delete removes a proxy's file. Deleting a proxy that was never persisted
+// is not an error.
+func (f *JSONFileProxyStore) Delete(id string) error {
+	if err := os.Remove(f.path(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete proxy %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/revcopy/crawlers/amazon/internal/config"
+)
+
+// syncProxiesFromFile loads fileConfigPath (if set) and applies it at
+// startup, logging rather than failing construction if the file is missing
+// or invalid.
+func (s *ProxyService) syncProxiesFromFile() {
+	if s.fileConfigPath == "" {
+		return
+	}
+
+	added, updated, removed, err := s.ReloadProxiesFromFile()
+	if err != nil {
+		log.Printf("failed to load %s, starting without file-provisioned proxies: %v", s.fileConfigPath, err)
+		return
+	}
+	log.Printf("loaded proxies.yaml: %d added, %d updated, %d removed", added, updated, removed)
+}
+
+// ReloadProxiesFromFile re-reads fileConfigPath and diffs it against the
+// in-memory FromFile proxies: entries new to the file are added, entries
+// already present are updated in place (preserving health state and
+// in-flight assignments, since those ride on the *ProxyEntry pointer rather
+// than the map lookup), and FromFile proxies no longer listed are removed.
+// API-created proxies (FromFile=false) are never touched by a reload.
+func (s *ProxyService) ReloadProxiesFromFile() (added, updated, removed int, err error) {
+	if s.fileConfigPath == "" {
+		return 0, 0, 0, fmt.Errorf("no proxies config file configured")
+	}
+
+	entries, err := config.LoadProxiesFile(s.fileConfigPath)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	desired := make(map[string]config.ProxyFileEntry, len(entries))
+	for _, e := range entries {
+		desired[e.ID] = e
+	}
+
+	s.mutex.Lock()
+	now := time.Now()
+
+	for id, e := range desired {
+		if existing, ok := s.proxies[id]; ok {
+			existing.Name = e.Name
+			existing.Username = e.Username
+			if e.Password != "" {
+				existing.Password = e.Password
+			}
+			existing.Host = e.Host
+			existing.Port = e.Port
+			existing.Active = e.Active
+			existing.Marketplaces = e.Marketplaces
+			existing.UpdatedAt = now
+			existing.FromFile = true
+			updated++
+			continue
+		}
+
+		s.proxies[id] = &ProxyEntry{
+			ID:              id,
+			Name:            e.Name,
+			Username:        e.Username,
+			Password:        e.Password,
+			Host:            e.Host,
+			Port:            e.Port,
+			Active:          e.Active,
+			Marketplaces:    e.Marketplaces,
+			Healthy:         true,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+			CreatedBy:       "proxies.yaml",
+			FromFile:        true,
+			concurrentLimit: make(chan struct{}, defaultConcurrentLimit),
+		}
+		if s.defaultProxyID == "" {
+			s.defaultProxyID = id
+		}
+		added++
+	}
+
+	// A FromFile proxy no longer listed is dropped from the pool, but its
+	// user assignments are left alone: GetUserProxy already reports
+	// "assigned proxy not found" for a stale assignment, and any request
+	// already dispatched through it holds its own *ProxyEntry and finishes
+	// unaffected.
+	for id, p := range s.proxies {
+		if p.FromFile {
+			if _, ok := desired[id]; !ok {
+				delete(s.proxies, id)
+				removed++
+			}
+		}
+	}
+
+	s.mutex.Unlock()
+	s.notifyChanged()
+
+	return added, updated, removed, nil
+}
+
+// WatchProxiesFile starts a background fsnotify watcher on fileConfigPath's
+// directory so edits to the file trigger the same reload path as an
+// explicit POST /proxies/reload, without waiting for a restart. It's a
+// no-op if fileConfigPath is unset. The watcher stops when Stop is called.
+func (s *ProxyService) WatchProxiesFile() error {
+	if s.fileConfigPath == "" {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start proxies.yaml watcher: %w", err)
+	}
+
+	dir := filepath.Dir(s.fileConfigPath)
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+	s.fileWatcher = watcher
+
+	go s.runFileWatcher(watcher)
+
+	return nil
+}
+
+// runFileWatcher processes watcher events until Stop closes
+// s.stopHealthCheck, reloading whenever fileConfigPath itself is written or
+// recreated (editors commonly replace a file rather than write in place).
+func (s *ProxyService) runFileWatcher(watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+
+	target := filepath.Clean(s.fileConfigPath)
+	for {
+		select {
+		case <-s.stopHealthCheck:
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != target {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if _, _, _, err := s.ReloadProxiesFromFile(); err != nil {
+				log.Printf("failed to reload %s: %v", s.fileConfigPath, err)
+			}
+		case watchErr, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("proxies.yaml watcher error: %v", watchErr)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ProxyGroupStrategy selects which member of a ProxyGroup a lookup resolves
+// to.
+type ProxyGroupStrategy string
+
+const (
+	// StrategySelect always returns the member last chosen via
+	// SelectProxyGroupMember (or the first member if none was chosen yet).
+	StrategySelect ProxyGroupStrategy = "select"
+	// StrategyRoundRobin cycles through eligible members on every lookup.
+	StrategyRoundRobin ProxyGroupStrategy = "round-robin"
+	// StrategyRandom picks a uniformly random eligible member per lookup.
+	StrategyRandom ProxyGroupStrategy = "random"
+	// StrategyFallback always prefers the first eligible member in ProxyIDs
+	// order, falling through to the next only when an earlier one is
+	// unhealthy, inactive, or draining.
+	StrategyFallback ProxyGroupStrategy = "fallback"
+	// StrategyURLTest behaves like StrategyFallback but orders eligible
+	// members by their last measured delay (see TestProxyDelay) instead of
+	// ProxyIDs order, so the group always routes through its fastest member.
+	StrategyURLTest ProxyGroupStrategy = "url-test"
+)
+
+const defaultGroupStickyMinutes = 10
+
+// stickyGroupBind remembers the member a username was last routed to, so
+// repeat lookups from the same user land on the same proxy until it expires
+// or becomes ineligible.
+type stickyGroupBind struct {
+	proxyID string
+	expires time.Time
+}
+
+// ProxyGroup is a named, ordered set of proxy IDs resolved to a single
+// concrete proxy per lookup according to Strategy. Groups back the
+// /api/v1/proxy-groups endpoints and can be assigned to a user in place of a
+// single proxy (see AssignProxyToUser).
+type ProxyGroup struct {
+	ID               string             `json:"id"`
+	Name             string             `json:"name"`
+	ProxyIDs         []string           `json:"proxy_ids"`
+	Strategy         ProxyGroupStrategy `json:"strategy"`
+	StickyTTLMinutes int                `json:"sticky_ttl_minutes,omitempty"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+	CreatedBy        string             `json:"created_by"`
+
+	mu          sync.Mutex
+	rrIndex     int
+	selectedID  string
+	stickyBinds map[string]stickyGroupBind
+}
+
+// stickyTTL returns how long a resolved member is pinned to a username
+// before the next lookup is free to re-resolve, defaulting to
+// defaultGroupStickyMinutes when unset.
+func (g *ProxyGroup) stickyTTL() time.Duration {
+	if g.StickyTTLMinutes <= 0 {
+		return defaultGroupStickyMinutes * time.Minute
+	}
+	return time.Duration(g.StickyTTLMinutes) * time.Minute
+}
+
+// ProxyGroupResponse is the API representation of a ProxyGroup.
+type ProxyGroupResponse struct {
+	ID               string             `json:"id"`
+	Name             string             `json:"name"`
+	ProxyIDs         []string           `json:"proxy_ids"`
+	Strategy         ProxyGroupStrategy `json:"strategy"`
+	StickyTTLMinutes int                `json:"sticky_ttl_minutes,omitempty"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+	CreatedBy        string             `json:"created_by"`
+}
+
+func (g *ProxyGroup) toResponse() *ProxyGroupResponse {
+	return &ProxyGroupResponse{
+		ID:               g.ID,
+		Name:             g.Name,
+		ProxyIDs:         g.ProxyIDs,
+		Strategy:         g.Strategy,
+		StickyTTLMinutes: g.StickyTTLMinutes,
+		CreatedAt:        g.CreatedAt,
+		UpdatedAt:        g.UpdatedAt,
+		CreatedBy:        g.CreatedBy,
+	}
+}
+
+// CreateProxyGroupRequest represents a proxy group creation request.
+type CreateProxyGroupRequest struct {
+	ID               string             `json:"id" binding:"required"`
+	Name             string             `json:"name" binding:"required"`
+	ProxyIDs         []string           `json:"proxy_ids" binding:"required,min=1"`
+	Strategy         ProxyGroupStrategy `json:"strategy" binding:"required"`
+	StickyTTLMinutes int                `json:"sticky_ttl_minutes,omitempty"`
+}
+
+// UpdateProxyGroupRequest represents a proxy group update request.
+type UpdateProxyGroupRequest struct {
+	Name             string             `json:"name,omitempty"`
+	ProxyIDs         []string           `json:"proxy_ids,omitempty"`
+	Strategy         ProxyGroupStrategy `json:"strategy,omitempty"`
+	StickyTTLMinutes int                `json:"sticky_ttl_minutes,omitempty"`
+}
+
+// SelectProxyGroupMemberRequest pins a select-strategy group to one member.
+type SelectProxyGroupMemberRequest struct {
+	ProxyID string `json:"proxy_id" binding:"required"`
+}
+
+func validProxyGroupStrategy(strategy ProxyGroupStrategy) bool {
+	switch strategy {
+	case StrategySelect, StrategyRoundRobin, StrategyRandom, StrategyFallback, StrategyURLTest:
+		return true
+	default:
+		return false
+	}
+}
+
+// CreateProxyGroup creates a new proxy group. Every ID in req.ProxyIDs must
+// already exist in the proxy pool.
+func (s *ProxyService) CreateProxyGroup(req CreateProxyGroupRequest, createdBy string) (*ProxyGroupResponse, error) {
+	if !validProxyGroupStrategy(req.Strategy) {
+		return nil, fmt.Errorf("invalid strategy %q", req.Strategy)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.proxyGroups[req.ID]; exists {
+		return nil, fmt.Errorf("proxy group already exists")
+	}
+
+	for _, id := range req.ProxyIDs {
+		if _, exists := s.proxies[id]; !exists {
+			return nil, fmt.Errorf("proxy %q not found", id)
+		}
+	}
+
+	now := time.Now()
+	group := &ProxyGroup{
+		ID:               req.ID,
+		Name:             req.Name,
+		ProxyIDs:         req.ProxyIDs,
+		Strategy:         req.Strategy,
+		StickyTTLMinutes: req.StickyTTLMinutes,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		CreatedBy:        createdBy,
+		stickyBinds:      make(map[string]stickyGroupBind),
+	}
+	s.proxyGroups[req.ID] = group
+
+	return group.toResponse(), nil
+}
+
+// GetProxyGroup returns a single proxy group by ID.
+func (s *ProxyService) GetProxyGroup(groupID string) (*ProxyGroupResponse, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	group, exists := s.proxyGroups[groupID]
+	if !exists {
+		return nil, fmt.Errorf("proxy group not found")
+	}
+	return group.toResponse(), nil
+}
+
+// ListProxyGroups returns every proxy group.
+func (s *ProxyService) ListProxyGroups() []*ProxyGroupResponse {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	groups := make([]*ProxyGroupResponse, 0, len(s.proxyGroups))
+	for _, group := range s.proxyGroups {
+		groups = append(groups, group.toResponse())
+	}
+	return groups
+}
+
+// UpdateProxyGroup applies a partial update to an existing proxy group.
+func (s *ProxyService) UpdateProxyGroup(groupID string, req UpdateProxyGroupRequest) (*ProxyGroupResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	group, exists := s.proxyGroups[groupID]
+	if !exists {
+		return nil, fmt.Errorf("proxy group not found")
+	}
+
+	if req.Name != "" {
+		group.Name = req.Name
+	}
+	if len(req.ProxyIDs) > 0 {
+		for _, id := range req.ProxyIDs {
+			if _, exists := s.proxies[id]; !exists {
+				return nil, fmt.Errorf("proxy %q not found", id)
+			}
+		}
+		group.ProxyIDs = req.ProxyIDs
+	}
+	if req.Strategy != "" {
+		if !validProxyGroupStrategy(req.Strategy) {
+			return nil, fmt.Errorf("invalid strategy %q", req.Strategy)
+		}
+		group.Strategy = req.Strategy
+	}
+	if req.StickyTTLMinutes > 0 {
+		group.StickyTTLMinutes = req.StickyTTLMinutes
+	}
+	group.UpdatedAt = time.Now()
+
+	return group.toResponse(), nil
+}
+
+// DeleteProxyGroup removes a proxy group and clears any user assignments
+// pointing at it.
+func (s *ProxyService) DeleteProxyGroup(groupID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.proxyGroups[groupID]; !exists {
+		return fmt.Errorf("proxy group not found")
+	}
+	delete(s.proxyGroups, groupID)
+
+	for username, assignedGroup := range s.userGroups {
+		if assignedGroup == groupID {
+			delete(s.userGroups, username)
+		}
+	}
+
+	return nil
+}
+
+// SelectProxyGroupMember pins a "select"-strategy group to proxyID, which
+// must already be a member of the group. It's a no-op error for any other
+// strategy, which resolve members automatically instead.
+func (s *ProxyService) SelectProxyGroupMember(groupID, proxyID string) error {
+	s.mutex.RLock()
+	group, exists := s.proxyGroups[groupID]
+	s.mutex.RUnlock()
+	if !exists {
+		return fmt.Errorf("proxy group not found")
+	}
+
+	if group.Strategy != StrategySelect {
+		return fmt.Errorf("group strategy is %q, not %q", group.Strategy, StrategySelect)
+	}
+
+	found := false
+	for _, id := range group.ProxyIDs {
+		if id == proxyID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("proxy %q is not a member of this group", proxyID)
+	}
+
+	group.mu.Lock()
+	group.selectedID = proxyID
+	group.mu.Unlock()
+
+	return nil
+}
+
+// eligibleGroupMembers returns the group's members that are currently
+// usable, preserving ProxyIDs order. Callers must hold at least s.mutex.RLock.
+func (s *ProxyService) eligibleGroupMembers(group *ProxyGroup) []*ProxyEntry {
+	eligible := make([]*ProxyEntry, 0, len(group.ProxyIDs))
+	for _, id := range group.ProxyIDs {
+		entry, exists := s.proxies[id]
+		if !exists || !entry.Active || !entry.Healthy || entry.Draining {
+			continue
+		}
+		eligible = append(eligible, entry)
+	}
+	return eligible
+}
+
+// resolveGroup picks the concrete proxy a username's lookup resolves to for
+// group, according to group.Strategy. Callers must hold at least
+// s.mutex.RLock; it additionally takes group.mu to update strategy state.
+func (s *ProxyService) resolveGroup(group *ProxyGroup, username string) *ProxyEntry {
+	eligible := s.eligibleGroupMembers(group)
+	if len(eligible) == 0 {
+		return nil
+	}
+
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	if bind, ok := group.stickyBinds[username]; ok && time.Now().Before(bind.expires) {
+		for _, entry := range eligible {
+			if entry.ID == bind.proxyID {
+				return entry
+			}
+		}
+	}
+
+	var chosen *ProxyEntry
+	switch group.Strategy {
+	case StrategySelect:
+		for _, entry := range eligible {
+			if entry.ID == group.selectedID {
+				chosen = entry
+				break
+			}
+		}
+		if chosen == nil {
+			chosen = eligible[0]
+		}
+	case StrategyRoundRobin:
+		chosen = eligible[group.rrIndex%len(eligible)]
+		group.rrIndex++
+	case StrategyRandom:
+		chosen = eligible[rand.Intn(len(eligible))]
+	case StrategyFallback:
+		chosen = eligible[0]
+	case StrategyURLTest:
+		chosen = eligible[0]
+		bestDelay, _ := proxyDisplayDelay(chosen)
+		for _, entry := range eligible[1:] {
+			delay, _ := proxyDisplayDelay(entry)
+			if delay > 0 && (bestDelay <= 0 || delay < bestDelay) {
+				chosen, bestDelay = entry, delay
+			}
+		}
+	default:
+		chosen = eligible[0]
+	}
+
+	if group.stickyBinds == nil {
+		group.stickyBinds = make(map[string]stickyGroupBind)
+	}
+	group.stickyBinds[username] = stickyGroupBind{proxyID: chosen.ID, expires: time.Now().Add(group.stickyTTL())}
+
+	return chosen
+}
+
+// resolveUserProxy returns the proxy a username's request should use,
+// checking a direct assignment or group assignment (see AssignProxyToUser)
+// before falling back to the pool's default proxy. Callers must hold at
+// least s.mutex.RLock.
+func (s *ProxyService) resolveUserProxy(username string) (*ProxyEntry, error) {
+	if groupID, ok := s.userGroups[username]; ok {
+		group, exists := s.proxyGroups[groupID]
+		if !exists {
+			return nil, fmt.Errorf("assigned proxy group not found")
+		}
+		entry := s.resolveGroup(group, username)
+		if entry == nil {
+			return nil, fmt.Errorf("no healthy proxy available in assigned group")
+		}
+		return entry, nil
+	}
+
+	proxyID, ok := s.userProxies[username]
+	if !ok {
+		proxyID = s.defaultProxyID
+	}
+
+	entry, exists := s.proxies[proxyID]
+	if !exists {
+		return nil, fmt.Errorf("assigned proxy not found")
+	}
+	return entry, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"sort"
+	"strings"
+)
+
+// defaultListLimit and maxListLimit bound the limit accepted by
+// QueryProxies/QueryUserProxyAssignments, mirroring the page/per_page
+// defaults handlers.paginate uses for other list endpoints.
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// normalizeListWindow applies this package's limit/offset defaults: a
+// non-positive limit becomes defaultListLimit, anything over maxListLimit is
+// capped, and a negative offset becomes 0.
+func normalizeListWindow(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// QueryProxiesOptions filters and paginates a ListProxies-style query.
+// Filters are ANDed; a zero value means "no filter" for that field, except
+// IDs where an empty slice means "all".
+type QueryProxiesOptions struct {
+	IDs       []string
+	Name      string // substring match, case-insensitive
+	Host      string // substring match, case-insensitive
+	Active    *bool
+	Assigned  *bool // true: proxy has >=1 direct user assignment
+	Unhealthy *bool
+	Limit     int
+	Offset    int
+}
+
+// QueryProxies returns the page of proxies matching opts, ordered by name
+// then ID so pagination is stable across calls, plus the total number of
+// matches before pagination was applied.
+func (s *ProxyService) QueryProxies(opts QueryProxiesOptions) (items []*ProxyResponse, total int) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var idFilter map[string]bool
+	if len(opts.IDs) > 0 {
+		idFilter = make(map[string]bool, len(opts.IDs))
+		for _, id := range opts.IDs {
+			idFilter[id] = true
+		}
+	}
+
+	assignedIDs := make(map[string]bool, len(s.userProxies))
+	for _, proxyID := range s.userProxies {
+		assignedIDs[proxyID] = true
+	}
+
+	nameFilter := strings.ToLower(opts.Name)
+	hostFilter := strings.ToLower(opts.Host)
+
+	matched := make([]*ProxyEntry, 0, len(s.proxies))
+	for id, proxy := range s.proxies {
+		if idFilter != nil && !idFilter[id] {
+			continue
+		}
+		if nameFilter != "" && !strings.Contains(strings.ToLower(proxy.Name), nameFilter) {
+			continue
+		}
+		if hostFilter != "" && !strings.Contains(strings.ToLower(proxy.Host), hostFilter) {
+			continue
+		}
+		if opts.Active != nil && proxy.Active != *opts.Active {
+			continue
+		}
+		if opts.Unhealthy != nil && (!proxy.Healthy) != *opts.Unhealthy {
+			continue
+		}
+		if opts.Assigned != nil && assignedIDs[id] != *opts.Assigned {
+			continue
+		}
+		matched = append(matched, proxy)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].Name != matched[j].Name {
+			return matched[i].Name < matched[j].Name
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	total = len(matched)
+	limit, offset := normalizeListWindow(opts.Limit, opts.Offset)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	items = make([]*ProxyResponse, 0, end-offset)
+	for _, proxy := range matched[offset:end] {
+		items = append(items, proxyToResponse(proxy))
+	}
+	return items, total
+}
+
+// UserProxyAssignment is one row of a QueryUserProxyAssignments result: a
+// username together with the proxy its assignment currently resolves to
+// (resolving a group assignment to its current member, see resolveGroup).
+type UserProxyAssignment struct {
+	Username string         `json:"username"`
+	GroupID  string         `json:"group_id,omitempty"`
+	Proxy    *ProxyResponse `json:"proxy"`
+}
+
+// QueryAssignmentsOptions filters and paginates a ListUserProxyAssignments-
+// style query. Filters are ANDed; a zero value means "no filter".
+type QueryAssignmentsOptions struct {
+	Username string // prefix match
+	ProxyID  string
+	GroupID  string
+	Limit    int
+	Offset   int
+}
+
+// QueryUserProxyAssignments returns the page of assignments matching opts,
+// ordered by username so pagination is stable, plus the total number of
+// matches before pagination was applied.
+func (s *ProxyService) QueryUserProxyAssignments(opts QueryAssignmentsOptions) (items []*UserProxyAssignment, total int) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var rows []*UserProxyAssignment
+
+	if opts.GroupID == "" {
+		for username, proxyID := range s.userProxies {
+			if opts.Username != "" && !strings.HasPrefix(username, opts.Username) {
+				continue
+			}
+			if opts.ProxyID != "" && proxyID != opts.ProxyID {
+				continue
+			}
+			proxy, exists := s.proxies[proxyID]
+			if !exists {
+				continue
+			}
+			rows = append(rows, &UserProxyAssignment{Username: username, Proxy: proxyToResponse(proxy)})
+		}
+	}
+
+	for username, groupID := range s.userGroups {
+		if opts.GroupID != "" && groupID != opts.GroupID {
+			continue
+		}
+		if opts.Username != "" && !strings.HasPrefix(username, opts.Username) {
+			continue
+		}
+		group, exists := s.proxyGroups[groupID]
+		if !exists {
+			continue
+		}
+		proxy := s.resolveGroup(group, username)
+		if proxy == nil {
+			continue
+		}
+		if opts.ProxyID != "" && proxy.ID != opts.ProxyID {
+			continue
+		}
+		rows = append(rows, &UserProxyAssignment{Username: username, GroupID: groupID, Proxy: proxyToResponse(proxy)})
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Username < rows[j].Username })
+
+	total = len(rows)
+	limit, offset := normalizeListWindow(opts.Limit, opts.Offset)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	return rows[offset:end], total
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ProxyStore persists proxy configurations so they survive restarts.
+// Implementations are responsible for encrypting sensitive fields at rest.
+type ProxyStore interface {
+	Load() ([]*ProxyEntry, error)
+	Save(entry *ProxyEntry) error
+	Delete(id string) error
+	List() ([]*ProxyEntry, error)
+}
+
+var proxyBucket = []byte("proxies")
+
+// BoltProxyStore is the default ProxyStore, backed by an embedded BoltDB file.
+// Passwords are encrypted with AES-GCM before being written to disk.
+type BoltProxyStore struct {
+	db  *bolt.DB
+	key [32]byte
+}
+
+// NewBoltProxyStore opens (creating if necessary) a BoltDB file at path and
+// returns a store that encrypts proxy passwords using key. If key is empty,
+// a key is derived from a fixed fallback passphrase and a warning is logged;
+// this should only happen in local/dev environments.
+func NewBoltProxyStore(path string, key []byte) (*BoltProxyStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create proxy store directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open proxy store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(proxyBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize proxy store bucket: %w", err)
+	}
+
+	store := &BoltProxyStore{db: db}
+	store.key = deriveEncryptionKey(key)
+
+	return store, nil
+}
+
+// deriveEncryptionKey returns a 32-byte AES-256 key. If key is non-empty it
+// is hashed down to 32 bytes; otherwise a fallback key is derived from a
+// fixed passphrase and a warning is logged, since that offers no real
+// confidentiality against anyone with access to the binary.
+func deriveEncryptionKey(key []byte) [32]byte {
+	if len(key) == 0 {
+		log.Println("WARNING: PROXY_ENCRYPTION_KEY not set, deriving a fallback key; set it in production")
+		return sha256.Sum256([]byte("revcopy-amazon-crawler-fallback-key"))
+	}
+	return sha256.Sum256(key)
+}
+
+// Close releases the underlying BoltDB file handle.
+func (b *BoltProxyStore) Close() error {
+	return b.db.Close()
+}
+
+// Load returns every persisted proxy, with passwords decrypted.
+func (b *BoltProxyStore) Load() ([]*ProxyEntry, error) {
+	return b.List()
+}
+
+// List returns every persisted proxy, with passwords decrypted.
+func (b *BoltProxyStore) List() ([]*ProxyEntry, error) {
+	var entries []*ProxyEntry
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(proxyBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var stored storedProxyEntry
+			if err := json.Unmarshal(v, &stored); err != nil {
+				return fmt.Errorf("failed to decode proxy %s: %w", k, err)
+			}
+
+			entry, err := stored.toEntry(b.key)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt proxy %s: %w", k, err)
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+
+	return entries, err
+}
+
+// Save upserts entry, encrypting its password before writing to disk.
+func (b *BoltProxyStore) Save(entry *ProxyEntry) error {
+	stored, err := newStoredProxyEntry(entry, b.key)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt proxy %s: %w", entry.ID, err)
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode proxy %s: %w", entry.ID, err)
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(proxyBucket).Put([]byte(entry.ID), data)
+	})
+}
+
+// Delete removes a proxy from the store by ID.
+func (b *BoltProxyStore) Delete(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(proxyBucket).Delete([]byte(id))
+	})
+}
+
+// storedProxyEntry is the on-disk representation of a ProxyEntry, with the
+// password field replaced by its AES-GCM ciphertext.
+type storedProxyEntry struct {
+	ProxyEntry
+	EncryptedPassword string `json:"encrypted_password"`
+}
+
+func newStoredProxyEntry(entry *ProxyEntry, key [32]byte) (*storedProxyEntry, error) {
+	ciphertext, err := encryptSecret(entry.Password, key)
+	if err != nil {
+		return nil, err
+	}
+
+	copyEntry := *entry
+	copyEntry.Password = "" // never persist the plaintext password
+	copyEntry.concurrentLimit = nil
+
+	return &storedProxyEntry{
+		ProxyEntry:        copyEntry,
+		EncryptedPassword: ciphertext,
+	}, nil
+}
+
+func (s *storedProxyEntry) toEntry(key [32]byte) (*ProxyEntry, error) {
+	password, err := decryptSecret(s.EncryptedPassword, key)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := s.ProxyEntry
+	entry.Password = password
+	entry.concurrentLimit = make(chan struct{}, defaultConcurrentLimit)
+	return &entry, nil
+}
+
+// encryptSecret encrypts plaintext with AES-GCM and returns it hex-encoded
+// (nonce prefixed).
+func encryptSecret(plaintext string, key [32]byte) (string, error) {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return hex.EncodeToString(ciphertext), nil
+}
+
+// decryptSecret reverses encryptSecret.
+func decryptSecret(encoded string, key [32]byte) (string, error) {
+	if encoded == "" {
+		return "", nil
+	}
+
+	ciphertext, err := hex.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted payload: %w", err)
+	}
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, data := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, data, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+
+	return string(plaintext), nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import "errors"
+
+// Permission strings gate specific API actions. They are embedded in a
+// user's JWT via Claims.Permissions so middleware.RequirePermission can
+// check them without a second lookup against the role store.
+const (
+	PermissionAmazonScrape   = "amazon:scrape"
+	PermissionAmazonBulk     = "amazon:bulk"
+	PermissionProxyRead      = "proxy:read"
+	PermissionProxyWrite     = "proxy:write"
+	PermissionAnalyticsRead  = "analytics:read"
+	PermissionAnalyticsWrite = "analytics:write"
+	PermissionUsersAdmin     = "users:admin"
+)
+
+// Role owns a named set of permissions that every user assigned to it
+// inherits.
+type Role struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// Has reports whether perm is granted by r.
+func (r Role) Has(perm string) bool {
+	for _, p := range r.Permissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateRoleRequest represents a role creation request.
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Permissions []string `json:"permissions"`
+}
+
+// UpdateRoleRequest represents a role update request; Permissions replaces
+// the role's entire permission set.
+type UpdateRoleRequest struct {
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// defaultRoles seeds the built-in roles matching createDefaultUsers.
+func defaultRoles() map[string]*Role {
+	return map[string]*Role{
+		"admin": {
+			Name: "admin",
+			Permissions: []string{
+				PermissionAmazonScrape, PermissionAmazonBulk,
+				PermissionProxyRead, PermissionProxyWrite,
+				PermissionAnalyticsRead, PermissionAnalyticsWrite,
+				PermissionUsersAdmin,
+			},
+		},
+		"crawler": {
+			Name:        "crawler",
+			Permissions: []string{PermissionAmazonScrape, PermissionAmazonBulk, PermissionProxyRead},
+		},
+		"analytics": {
+			Name:        "analytics",
+			Permissions: []string{PermissionAnalyticsRead, PermissionAnalyticsWrite},
+		},
+		"user": {
+			Name:        "user",
+			Permissions: []string{PermissionAmazonScrape},
+		},
+	}
+}
+
+// GetRole returns the role registered under name.
+func (s *AuthService) GetRole(name string) (*Role, error) {
+	s.rolesMu.RLock()
+	defer s.rolesMu.RUnlock()
+
+	role, exists := s.roles[name]
+	if !exists {
+		return nil, errors.New("role not found")
+	}
+	return role, nil
+}
+
+// ListRoles lists every registered role.
+func (s *AuthService) ListRoles() []*Role {
+	s.rolesMu.RLock()
+	defer s.rolesMu.RUnlock()
+
+	roles := make([]*Role, 0, len(s.roles))
+	for _, role := range s.roles {
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+// CreateRole registers a new role with the given permission set.
+func (s *AuthService) CreateRole(req CreateRoleRequest) (*Role, error) {
+	s.rolesMu.Lock()
+	defer s.rolesMu.Unlock()
+
+	if _, exists := s.roles[req.Name]; exists {
+		return nil, errors.New("role already exists")
+	}
+
+	role := &Role{Name: req.Name, Permissions: req.Permissions}
+	s.roles[req.Name] = role
+	return role, nil
+}
+
+// UpdateRole replaces the permission set of an existing role.
+func (s *AuthService) UpdateRole(name string, req UpdateRoleRequest) (*Role, error) {
+	s.rolesMu.Lock()
+	defer s.rolesMu.Unlock()
+
+	role, exists := s.roles[name]
+	if !exists {
+		return nil, errors.New("role not found")
+	}
+
+	role.Permissions = req.Permissions
+	return role, nil
+}
+
+// DeleteRole removes a role. The built-in admin role cannot be deleted,
+// mirroring DeleteUser's protection of the admin user.
+func (s *AuthService) DeleteRole(name string) error {
+	if name == "admin" {
+		return errors.New("cannot delete admin role")
+	}
+
+	s.rolesMu.Lock()
+	defer s.rolesMu.Unlock()
+
+	if _, exists := s.roles[name]; !exists {
+		return errors.New("role not found")
+	}
+
+	delete(s.roles, name)
+	return nil
+}
+
+// permissionsFor returns the permission set granted by roleName, or nil if
+// roleName is not a registered role.
+func (s *AuthService) permissionsFor(roleName string) []string {
+	s.rolesMu.RLock()
+	defer s.rolesMu.RUnlock()
+
+	role, exists := s.roles[roleName]
+	if !exists {
+		return nil
+	}
+	return role.Permissions
+}
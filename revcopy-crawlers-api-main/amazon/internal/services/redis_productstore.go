@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisProductStore is the default ProductStore, backed by Redis; TTL is
+// enforced natively via Redis key expiry rather than a stored expires_at.
+type RedisProductStore struct {
+	client *redis.Client
+}
+
+// NewRedisProductStore connects to redisURL and returns a ProductStore
+// backed by Redis.
+func NewRedisProductStore(redisURL string) (*RedisProductStore, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse REDIS_URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping Redis: %w", err)
+	}
+
+	return &RedisProductStore{client: client}, nil
+}
+
+// Close releases the underlying Redis connection.
+func (s *RedisProductStore) Close() error {
+	return s.client.Close()
+}
+
+// productCacheKey is the Redis key a marketplace+ASIN pair is stored under.
+func productCacheKey(marketplace, asin string) string {
+	return fmt.Sprintf("product:%s:%s", marketplace, asin)
+}
+
+func (s *RedisProductStore) Get(marketplace, asin string) (*CachedProduct, error) {
+	raw, err := s.client.Get(context.Background(), productCacheKey(marketplace, asin)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrProductNotCached
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cached CachedProduct
+	if err := json.Unmarshal(raw, &cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+func (s *RedisProductStore) Set(marketplace, asin string, product *AmazonProduct, ttl time.Duration) error {
+	raw, err := json.Marshal(CachedProduct{Product: product, LastRefreshed: time.Now()})
+	if err != nil {
+		return err
+	}
+	return s.client.Set(context.Background(), productCacheKey(marketplace, asin), raw, ttl).Err()
+}
+
+func (s *RedisProductStore) Delete(marketplace, asin string) error {
+	return s.client.Del(context.Background(), productCacheKey(marketplace, asin)).Err()
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/revcopy/crawlers/amazon/internal/config"
+)
+
+// blockedResourcePatterns are the URL globs dropped in the headless tab when
+// RendererConfig.BlockImagesAndFonts is set, since images and fonts never
+// affect the DOM structure a Shop parses.
+var blockedResourcePatterns = []string{
+	"*.png", "*.jpg", "*.jpeg", "*.gif", "*.webp", "*.svg",
+	"*.woff", "*.woff2", "*.ttf", "*.otf",
+}
+
+// RendererClient renders a page with headless Chrome via the Chrome
+// DevTools Protocol (chromedp), for pages whose real markup only appears
+// after JS execution. Concurrent renders are capped at
+// cfg.MaxConcurrentTabs by tabs, a buffered channel used as a counting
+// semaphore.
+type RendererClient struct {
+	cfg  config.RendererConfig
+	tabs chan struct{}
+}
+
+// NewRendererClient creates a RendererClient from cfg.
+func NewRendererClient(cfg config.RendererConfig) *RendererClient {
+	maxTabs := cfg.MaxConcurrentTabs
+	if maxTabs <= 0 {
+		maxTabs = 1
+	}
+	return &RendererClient{cfg: cfg, tabs: make(chan struct{}, maxTabs)}
+}
+
+// enabled reports whether the renderer is configured to run at all.
+func (r *RendererClient) enabled() bool {
+	return r != nil && r.cfg.Enabled
+}
+
+// Render loads rawURL in a headless tab, waits for opts.RequireSelector (or
+// a short settle delay if unset), and returns the fully rendered HTML.
+func (r *RendererClient) Render(rawURL string, opts FetchOptions) (string, error) {
+	if !r.enabled() {
+		return "", fmt.Errorf("headless renderer is disabled")
+	}
+
+	r.tabs <- struct{}{}
+	defer func() { <-r.tabs }()
+
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), chromedp.DefaultExecAllocatorOptions[:]...)
+	defer cancelAlloc()
+
+	timeout := r.cfg.PageTimeout
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	ctx, cancelTimeout := context.WithTimeout(allocCtx, timeout)
+	defer cancelTimeout()
+
+	taskCtx, cancelTask := chromedp.NewContext(ctx)
+	defer cancelTask()
+
+	var tasks chromedp.Tasks
+	if r.cfg.BlockImagesAndFonts {
+		tasks = append(tasks,
+			network.Enable(),
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				return network.SetBlockedURLS(blockedResourcePatterns).Do(ctx)
+			}),
+		)
+	}
+
+	tasks = append(tasks, chromedp.Navigate(rawURL))
+	if opts.RequireSelector != "" {
+		tasks = append(tasks, chromedp.WaitVisible(opts.RequireSelector, chromedp.ByQuery))
+	} else {
+		tasks = append(tasks, chromedp.Sleep(2*time.Second))
+	}
+
+	var html string
+	tasks = append(tasks, chromedp.OuterHTML("html", &html))
+
+	if err := chromedp.Run(taskCtx, tasks...); err != nil {
+		return "", fmt.Errorf("headless render failed: %w", err)
+	}
+
+	return html, nil
+}
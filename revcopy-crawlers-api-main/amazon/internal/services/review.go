@@ -0,0 +1,115 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// reviewBlockSelector marks a single review on an Amazon product-reviews
+// page.
+const reviewBlockSelector = "[data-hook='review']"
+
+// reviewRatingPrefix matches the "X out of 5 stars" text Amazon prefixes
+// both the star-rating and (hidden) title elements with.
+var reviewRatingPrefix = regexp.MustCompile(`^\s*[\d.]+ out of 5 stars\s*`)
+
+// ReviewScraper walks an Amazon product's paginated /product-reviews/
+// listing, filtered separately by positive and critical star ratings, and
+// parses each review block into a Review. It exists so ScrapeProduct can
+// report real reviews instead of the hardcoded sample text the inline
+// product-page extraction used to fall back to.
+type ReviewScraper struct {
+	fetcher Fetcher
+}
+
+// NewReviewScraper creates a ReviewScraper that fetches pages through
+// fetcher.
+func NewReviewScraper(fetcher Fetcher) *ReviewScraper {
+	return &ReviewScraper{fetcher: fetcher}
+}
+
+// Scrape returns asin's positive and critical reviews, each paginated up to
+// maxPages or until a page comes back with no review blocks.
+func (r *ReviewScraper) Scrape(requestID, baseURL, asin string, maxPages int) (positive, negative []Review, err error) {
+	positive, err = r.scrapeFiltered(requestID, baseURL, asin, "positive", maxPages)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scrape positive reviews: %w", err)
+	}
+
+	negative, err = r.scrapeFiltered(requestID, baseURL, asin, "critical", maxPages)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scrape critical reviews: %w", err)
+	}
+
+	return positive, negative, nil
+}
+
+// scrapeFiltered walks a single filterByStar listing for asin.
+func (r *ReviewScraper) scrapeFiltered(requestID, baseURL, asin, filter string, maxPages int) ([]Review, error) {
+	var reviews []Review
+
+	for page := 1; page <= maxPages; page++ {
+		pageURL := fmt.Sprintf("%s/product-reviews/%s/?pageNumber=%d&filterByStar=%s", baseURL, asin, page, filter)
+
+		// No RequireSelector here: running out of reviews is a normal,
+		// expected empty page, not a sign the fetch was blocked.
+		body, err := r.fetcher.Fetch(requestID, pageURL, FetchOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reviews page %d: %w", page, err)
+		}
+
+		pageReviews := parseReviewBlocks(doc)
+		if len(pageReviews) == 0 {
+			break
+		}
+		reviews = append(reviews, pageReviews...)
+	}
+
+	return reviews, nil
+}
+
+// parseReviewBlocks parses every review on a product-reviews page.
+func parseReviewBlocks(doc *goquery.Document) []Review {
+	var reviews []Review
+	doc.Find(reviewBlockSelector).Each(func(i int, sel *goquery.Selection) {
+		reviews = append(reviews, parseReviewBlock(sel))
+	})
+	return reviews
+}
+
+// parseReviewBlock parses a single [data-hook='review'] element.
+func parseReviewBlock(sel *goquery.Selection) Review {
+	review := Review{
+		Title:    reviewRatingPrefix.ReplaceAllString(sel.Find("[data-hook='review-title']").Text(), ""),
+		Text:     strings.TrimSpace(sel.Find("[data-hook='review-body'] span").Text()),
+		Author:   strings.TrimSpace(sel.Find(".a-profile-name").First().Text()),
+		Date:     strings.TrimSpace(sel.Find("[data-hook='review-date']").Text()),
+		Verified: sel.Find("[data-hook='avp-badge']").Length() > 0,
+	}
+	review.Title = strings.TrimSpace(review.Title)
+
+	if rating, err := parseRating(sel.Find("[data-hook='review-star-rating']").Text()); err == nil {
+		review.Rating = int(rating)
+	}
+
+	return review
+}
+
+// reviewTexts extracts the review body text from reviews, in order, for
+// callers that only want the existing []string shape (AmazonProduct's
+// PositiveReviews/NegativeReviews fields).
+func reviewTexts(reviews []Review) []string {
+	texts := make([]string, 0, len(reviews))
+	for _, review := range reviews {
+		texts = append(texts, review.Text)
+	}
+	return texts
+}
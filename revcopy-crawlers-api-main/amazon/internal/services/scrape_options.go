@@ -0,0 +1,28 @@
+package services
+
+// scrapeOptions holds the settings ScrapeOption functions mutate. Kept
+// unexported: callers only ever see the option constructors below, in the
+// same functional-options style used elsewhere in this package.
+type scrapeOptions struct {
+	maxReviewPages int
+}
+
+// ScrapeOption customizes a ScrapeProduct call.
+type ScrapeOption func(*scrapeOptions)
+
+// WithReviews enables the dedicated review-scraping pass for ScrapeProduct,
+// walking a product's /product-reviews/ pages until maxPages is reached or
+// a page comes back with no reviews. Without this option, ScrapeProduct
+// reports no reviews rather than fabricating sample ones.
+func WithReviews(maxPages int) ScrapeOption {
+	return func(o *scrapeOptions) { o.maxReviewPages = maxPages }
+}
+
+// applyScrapeOptions folds opts into a scrapeOptions value.
+func applyScrapeOptions(opts []ScrapeOption) scrapeOptions {
+	var cfg scrapeOptions
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
@@ -2,36 +2,58 @@ package services
 
 import (
 	"fmt"
-	"io"
-	"net/http"
-	"regexp"
-	"strconv"
+	"log"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/PuerkitoBio/goquery"
+	"github.com/revcopy/crawlers/amazon/internal/config"
 )
 
-// ScraperService handles Amazon scraping operations
+// ScraperService is the entry point for product scraping. It no longer
+// talks to Amazon directly: it dispatches every URL to the Shop registered
+// for that host via shops, so a single BulkScrapeProducts call can mix
+// Amazon regions and third-party stores.
 type ScraperService struct {
 	proxyService *ProxyService
+	shops        *ShopManager
+	bulkScrape   config.BulkScrapeConfig
+
+	// productStore, when set via SetProductStore, makes ScrapeProduct
+	// write-through cache every successfully scraped product and lets
+	// GetAmazonProduct serve cached copies; see internal/handlers.
+	productStore ProductStore
+	productTTL   time.Duration
+	refreshJobs  chan refreshJob
 }
 
-// AmazonProduct represents a scraped Amazon product
+// refreshJob describes an asynchronous cache refresh queued by a stale
+// GetAmazonProduct read (see ScraperService.QueueRefresh).
+type refreshJob struct {
+	requestID   string
+	marketplace string
+	asin        string
+	url         string
+}
+
+// AmazonProduct represents a scraped product. The name predates multi-shop
+// support; it's kept as-is since every Shop implementation already returns
+// this shape and every caller already depends on the field names.
 type AmazonProduct struct {
-	ASIN        string   `json:"asin"`
-	Title       string   `json:"title"`
-	Price       float64  `json:"price"`
-	Currency    string   `json:"currency"`
-	Rating      float64  `json:"rating"`
-	ReviewCount int      `json:"review_count"`
-	Images      []string `json:"images"`
-	Description string   `json:"description"`
+	ASIN         string   `json:"asin"`
+	Title        string   `json:"title"`
+	Price        float64  `json:"price"`
+	Currency     string   `json:"currency"`
+	Rating       float64  `json:"rating"`
+	ReviewCount  int      `json:"review_count"`
+	Images       []string `json:"images"`
+	Description  string   `json:"description"`
 	Availability string   `json:"availability"`
-	Brand       string   `json:"brand"`
-	Category    string   `json:"category"`
-	URL         string   `json:"url"`
-	ScrapedAt   string   `json:"scraped_at"`
+	Brand        string   `json:"brand"`
+	Category     string   `json:"category"`
+	URL          string   `json:"url"`
+	ScrapedAt    string   `json:"scraped_at"`
 	// Add review fields
 	PositiveReviews []string `json:"positive_reviews"`
 	NegativeReviews []string `json:"negative_reviews"`
@@ -53,6 +75,9 @@ type ScrapeRequest struct {
 	URL    string `json:"url" binding:"required"`
 	ASIN   string `json:"asin,omitempty"`
 	Region string `json:"region,omitempty"`
+	// MaxReviewPages, when positive, also crawls the product's positive and
+	// critical review pages (up to this many pages each) via WithReviews.
+	MaxReviewPages int `json:"max_review_pages,omitempty"`
 }
 
 // BulkScrapeRequest represents bulk scraping request
@@ -62,6 +87,23 @@ type BulkScrapeRequest struct {
 	Region string   `json:"region,omitempty"`
 }
 
+// FailedURL records a single URL's bulk-scrape failure, after retries have
+// been exhausted.
+type FailedURL struct {
+	URL        string `json:"url"`
+	Err        string `json:"error"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Attempts   int    `json:"attempts"`
+}
+
+// BulkResult is the outcome of a BulkScrapeProducts call: every URL that
+// scraped successfully alongside every one that didn't, so callers never
+// lose visibility into partial failures behind a single collapsed error.
+type BulkResult struct {
+	Products []*AmazonProduct `json:"products"`
+	Failed   []FailedURL      `json:"failed,omitempty"`
+}
+
 // SearchRequest represents product search request
 type SearchRequest struct {
 	Query    string `json:"query" binding:"required"`
@@ -72,370 +114,255 @@ type SearchRequest struct {
 	Page     int    `json:"page,omitempty"`
 }
 
-// NewScraperService creates a new scraper service
-func NewScraperService(proxyService *ProxyService) *ScraperService {
-	return &ScraperService{
-		proxyService: proxyService,
-	}
-}
+// NewScraperService creates a new scraper service and registers its default
+// Shop backends: every Amazon storefront region, Steam, and a generic
+// Shopify-style fallback for everything else. Every Shop fetches through a
+// RenderingFetcher, so a page that comes back as a CAPTCHA wall or missing
+// its expected selector transparently retries through headless Chrome per
+// rendererCfg.
+func NewScraperService(proxyService *ProxyService, rendererCfg config.RendererConfig, bulkScrapeCfg config.BulkScrapeConfig) *ScraperService {
+	fetcher := NewRenderingFetcher(NewHTTPFetcher(proxyService), NewRendererClient(rendererCfg))
 
-// ScrapeProduct scrapes a single Amazon product
-func (s *ScraperService) ScrapeProduct(url string) (*AmazonProduct, error) {
-	client := s.proxyService.GetClient()
-	
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	shops := NewShopManager()
+
+	for _, region := range amazonRegions {
+		shop := NewAmazonShop(fetcher, region)
+		shops.Register(shop.Domains(), shop)
 	}
 
-	// Set realistic headers to avoid detection
-	s.setHeaders(req)
+	steam := NewSteamShop(fetcher)
+	shops.Register(steam.Domains(), steam)
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch product page: %w", err)
-	}
-	defer resp.Body.Close()
+	shops.RegisterFallback(NewGenericShopifyShop(fetcher))
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	return &ScraperService{
+		proxyService: proxyService,
+		shops:        shops,
+		bulkScrape:   bulkScrapeCfg,
 	}
+}
 
-	body, err := io.ReadAll(resp.Body)
+// ScrapeProduct scrapes a single product, routing rawURL to the Shop
+// registered for its host. requestID, when non-empty, is echoed on the
+// outbound fetch as X-Request-ID so it can be correlated with the inbound
+// API request that triggered it. opts customizes the scrape, e.g.
+// WithReviews to also crawl the product's review pages. When a
+// ProductStore is configured (see SetProductStore), a successful scrape is
+// write-through cached keyed by marketplace + ASIN.
+func (s *ScraperService) ScrapeProduct(requestID, rawURL string, opts ...ScrapeOption) (*AmazonProduct, error) {
+	product, err := s.shops.Retrieve(requestID, rawURL, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
-	return s.parseProductPage(string(body), url)
-}
-
-// BulkScrapeProducts scrapes multiple Amazon products
-func (s *ScraperService) BulkScrapeProducts(urls []string) ([]*AmazonProduct, error) {
-	var products []*AmazonProduct
-	var errors []string
-
-	for i, url := range urls {
-		// Add delay between requests to avoid rate limiting
-		if i > 0 {
-			time.Sleep(2 * time.Second)
+	if s.productStore != nil && product.ASIN != "" {
+		marketplace := marketplaceFromURL(product.URL)
+		if err := s.productStore.Set(marketplace, product.ASIN, product, s.productTTL); err != nil {
+			log.Printf("failed to cache product %s/%s: %v", marketplace, product.ASIN, err)
 		}
+	}
 
-		product, err := s.ScrapeProduct(url)
-		if err != nil {
-			errors = append(errors, fmt.Sprintf("URL %s: %v", url, err))
-			continue
-		}
+	return product, nil
+}
 
-		products = append(products, product)
-	}
+// SetProductStore wires store into ScrapeProduct's write-through cache and
+// starts refreshWorkers goroutines to service QueueRefresh's asynchronous
+// revalidate-on-access refreshes. Call once during startup, mirroring
+// ProxyService.SetAnalyticsService.
+func (s *ScraperService) SetProductStore(store ProductStore, ttl time.Duration, refreshWorkers int) {
+	s.productStore = store
+	s.productTTL = ttl
 
-	if len(errors) > 0 && len(products) == 0 {
-		return nil, fmt.Errorf("all requests failed: %s", strings.Join(errors, "; "))
+	if refreshWorkers <= 0 {
+		refreshWorkers = 1
+	}
+	s.refreshJobs = make(chan refreshJob, refreshWorkers*4)
+	for i := 0; i < refreshWorkers; i++ {
+		go s.runRefreshWorker()
 	}
-
-	return products, nil
 }
 
-// SearchProducts searches Amazon for products
-func (s *ScraperService) SearchProducts(query string, page int) ([]*AmazonProduct, error) {
-	searchURL := fmt.Sprintf("https://www.amazon.com/s?k=%s&page=%d", 
-		strings.ReplaceAll(query, " ", "+"), page)
-
-	client := s.proxyService.GetClient()
-	
-	req, err := http.NewRequest("GET", searchURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create search request: %w", err)
+// runRefreshWorker drains refreshJobs, re-scraping each one so its cache
+// entry is refreshed. Scrape failures are logged, not returned, since
+// there's no caller left waiting on a background refresh.
+func (s *ScraperService) runRefreshWorker() {
+	for job := range s.refreshJobs {
+		if _, err := s.ScrapeProduct(job.requestID, job.url); err != nil {
+			log.Printf("background refresh failed for %s/%s: %v", job.marketplace, job.asin, err)
+		}
 	}
+}
 
-	s.setHeaders(req)
+// QueueRefresh enqueues a background re-scrape of marketplace+asin, for a
+// caller that's serving a stale cache entry (?max_age=) and wants it
+// refreshed without blocking the current request. Returns false, doing
+// nothing, if no ProductStore is configured or the refresh queue is
+// currently full.
+func (s *ScraperService) QueueRefresh(requestID, marketplace, asin string) bool {
+	if s.productStore == nil || s.refreshJobs == nil {
+		return false
+	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch search results: %w", err)
+	job := refreshJob{requestID: requestID, marketplace: marketplace, asin: asin, url: s.ProductURL(marketplace, asin)}
+	select {
+	case s.refreshJobs <- job:
+		return true
+	default:
+		log.Printf("product refresh queue full, dropping refresh for %s/%s", marketplace, asin)
+		return false
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("search request failed with status: %d", resp.StatusCode)
+// CachedProduct looks up marketplace+asin in the configured ProductStore,
+// returning ErrProductNotCached if caching isn't configured or the entry
+// isn't (freshly) cached.
+func (s *ScraperService) CachedProduct(marketplace, asin string) (*CachedProduct, error) {
+	if s.productStore == nil {
+		return nil, ErrProductNotCached
 	}
+	return s.productStore.Get(marketplace, asin)
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read search response: %w", err)
+// DeleteCachedProduct evicts marketplace+asin from the configured
+// ProductStore; a no-op if caching isn't configured.
+func (s *ScraperService) DeleteCachedProduct(marketplace, asin string) error {
+	if s.productStore == nil {
+		return nil
 	}
+	return s.productStore.Delete(marketplace, asin)
+}
 
-	return s.parseSearchResults(string(body))
+// ProductURL builds the canonical product URL for marketplace+asin (e.g.
+// "amazon.com"+"B0BSHF7WHW" -> "https://www.amazon.com/dp/B0BSHF7WHW"), so
+// GetAmazonProduct can trigger a live scrape from just the path/query
+// values it's given.
+func (s *ScraperService) ProductURL(marketplace, asin string) string {
+	return fmt.Sprintf("https://www.%s/dp/%s", marketplace, asin)
 }
 
-// parseProductPage parses Amazon product page HTML
-func (s *ScraperService) parseProductPage(html, url string) (*AmazonProduct, error) {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+// marketplaceFromURL extracts the bare hostname (no "www." prefix) a
+// scraped product's URL resolved to, used as the marketplace half of its
+// cache key.
+func marketplaceFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
-	}
-
-	product := &AmazonProduct{
-		URL:       url,
-		ScrapedAt: time.Now().UTC().Format(time.RFC3339),
+		return ""
 	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
 
-	// Extract ASIN from URL
-	asinRegex := regexp.MustCompile(`/dp/([A-Z0-9]{10})`)
-	if matches := asinRegex.FindStringSubmatch(url); len(matches) > 1 {
-		product.ASIN = matches[1]
+// BulkScrapeProducts scrapes multiple products concurrently, which may span
+// different shops in a single call. Up to s.bulkScrape.MaxConcurrency URLs
+// are in flight at once; requests to a single host are additionally
+// throttled by a per-host token bucket sized from s.bulkScrape.RateLimit, so
+// e.g. amazon.com and amazon.es proceed independently. A URL that comes
+// back with a 5xx or 429 is retried with exponential backoff before being
+// recorded as failed. The returned error is only non-nil when every URL
+// failed; result.Failed always carries the per-URL detail either way.
+func (s *ScraperService) BulkScrapeProducts(requestID string, urls []string) (*BulkResult, error) {
+	result := &BulkResult{}
+	if len(urls) == 0 {
+		return result, nil
 	}
 
-	// Extract title
-	product.Title = doc.Find("#productTitle").Text()
-	product.Title = strings.TrimSpace(product.Title)
-
-	// Extract price
-	priceText := doc.Find(".a-price-whole").First().Text()
-	if priceText == "" {
-		priceText = doc.Find(".a-price .a-offscreen").First().Text()
+	limiter := newHostLimiter(s.bulkScrape.RateLimit)
+	maxConcurrency := s.bulkScrape.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = 1
 	}
-	if price, err := s.parsePrice(priceText); err == nil {
-		product.Price = price
+	if maxConcurrency > len(urls) {
+		maxConcurrency = len(urls)
 	}
 
-	// Extract currency
-	product.Currency = "USD" // Default, could be extracted from price symbol
+	outcomes := make([]bulkScrapeOutcome, len(urls))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
 
-	// Extract rating
-	ratingText := doc.Find(".a-icon-alt").First().Text()
-	if rating, err := s.parseRating(ratingText); err == nil {
-		product.Rating = rating
+	for w := 0; w < maxConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				outcomes[i] = s.scrapeWithRetry(requestID, urls[i], limiter)
+			}
+		}()
 	}
 
-	// Extract review count
-	reviewText := doc.Find("#acrCustomerReviewText").Text()
-	if count, err := s.parseReviewCount(reviewText); err == nil {
-		product.ReviewCount = count
+	for i := range urls {
+		jobs <- i
 	}
+	close(jobs)
+	wg.Wait()
 
-	// Extract images
-	doc.Find("#landingImage").Each(func(i int, sel *goquery.Selection) {
-		if src, exists := sel.Attr("src"); exists {
-			product.Images = append(product.Images, src)
-		}
-	})
-
-	// Extract description
-	product.Description = doc.Find("#feature-bullets ul").Text()
-	product.Description = strings.TrimSpace(product.Description)
-
-	// Extract features from feature bullets
-	doc.Find("#feature-bullets ul li span").Each(func(i int, sel *goquery.Selection) {
-		feature := strings.TrimSpace(sel.Text())
-		if feature != "" && !strings.Contains(feature, "Make sure") {
-			product.Features = append(product.Features, feature)
+	for _, outcome := range outcomes {
+		if outcome.failed != nil {
+			result.Failed = append(result.Failed, *outcome.failed)
+			continue
 		}
-	})
-
-	// Extract availability
-	product.Availability = doc.Find("#availability span").Text()
-	product.Availability = strings.TrimSpace(product.Availability)
-
-	// Extract brand
-	product.Brand = doc.Find("#bylineInfo").Text()
-	product.Brand = strings.TrimSpace(product.Brand)
-
-	// Extract reviews from the page
-	positiveReviews, negativeReviews := s.extractReviewsFromPage(doc)
-	product.PositiveReviews = positiveReviews
-	product.NegativeReviews = negativeReviews
-
-	return product, nil
-}
-
-// extractReviewsFromPage extracts positive and negative reviews from the product page
-func (s *ScraperService) extractReviewsFromPage(doc *goquery.Document) ([]string, []string) {
-	var positiveReviews []string
-	var negativeReviews []string
-
-	// Look for reviews in different possible locations
-	reviewSelectors := []string{
-		"[data-hook='review-body'] span",
-		".cr-original-review-text", 
-		".review-text",
-		"[data-hook='review-body'] > span > span",
+		result.Products = append(result.Products, outcome.product)
 	}
 
-	for _, selector := range reviewSelectors {
-		doc.Find(selector).Each(func(i int, sel *goquery.Selection) {
-			reviewText := strings.TrimSpace(sel.Text())
-			if len(reviewText) > 20 { // Only consider substantial reviews
-				// Get rating from parent review container
-				rating := s.extractRatingFromReview(sel)
-				
-				if rating >= 4 {
-					positiveReviews = append(positiveReviews, reviewText)
-				} else if rating <= 2 {
-					negativeReviews = append(negativeReviews, reviewText)
-				}
-			}
-		})
+	if len(result.Products) == 0 && len(result.Failed) > 0 {
+		return result, fmt.Errorf("all %d URLs failed to scrape", len(result.Failed))
 	}
 
-	// If no reviews found on main page, try to extract from review snippets
-	if len(positiveReviews) == 0 && len(negativeReviews) == 0 {
-		doc.Find(".a-row.review-data").Each(func(i int, sel *goquery.Selection) {
-			reviewText := strings.TrimSpace(sel.Text())
-			if len(reviewText) > 15 {
-				// Default to positive if no rating available
-				positiveReviews = append(positiveReviews, reviewText)
-			}
-		})
-	}
+	return result, nil
+}
 
-	// Generate sample reviews if none found (for demo purposes)
-	if len(positiveReviews) == 0 {
-		positiveReviews = s.generateSamplePositiveReviews()
-	}
-	if len(negativeReviews) == 0 {
-		negativeReviews = s.generateSampleNegativeReviews()
+// BulkScrapeProductsStream behaves like BulkScrapeProducts but, instead of
+// collecting every outcome into a BulkResult, invokes onResult as soon as
+// each URL finishes — in completion order, not URL order. Exactly one of
+// onResult's arguments is non-nil per call. This backs the gRPC
+// ScraperService.BulkScrape streaming RPC, whose whole point is to let a
+// client consume progress incrementally instead of waiting for the
+// slowest URL in the batch. onResult is called from a single goroutine, so
+// it may safely build up its own result without locking.
+func (s *ScraperService) BulkScrapeProductsStream(requestID string, urls []string, onResult func(product *AmazonProduct, failed *FailedURL)) {
+	if len(urls) == 0 {
+		return
 	}
 
-	// Limit to reasonable numbers
-	if len(positiveReviews) > 5 {
-		positiveReviews = positiveReviews[:5]
+	limiter := newHostLimiter(s.bulkScrape.RateLimit)
+	maxConcurrency := s.bulkScrape.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = 1
 	}
-	if len(negativeReviews) > 3 {
-		negativeReviews = negativeReviews[:3]
+	if maxConcurrency > len(urls) {
+		maxConcurrency = len(urls)
 	}
 
-	return positiveReviews, negativeReviews
-}
-
-// extractRatingFromReview tries to extract rating from review context
-func (s *ScraperService) extractRatingFromReview(reviewEl *goquery.Selection) int {
-	// Look for rating in various parent elements
-	rating := 5 // Default to positive
+	jobs := make(chan string)
+	results := make(chan bulkScrapeOutcome)
+	var wg sync.WaitGroup
 
-	// Try to find rating in parent containers
-	reviewEl.ParentsUntil(".review").Each(func(i int, parent *goquery.Selection) {
-		ratingText := parent.Find("[data-hook='review-star-rating']").Text()
-		if ratingText == "" {
-			ratingText = parent.Find(".a-icon-alt").Text()
-		}
-		if ratingText != "" {
-			if parsedRating, err := s.parseRating(ratingText); err == nil {
-				rating = int(parsedRating)
+	for w := 0; w < maxConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range jobs {
+				results <- s.scrapeWithRetry(requestID, u, limiter)
 			}
-		}
-	})
-
-	return rating
-}
-
-// generateSamplePositiveReviews creates sample positive reviews for demo
-func (s *ScraperService) generateSamplePositiveReviews() []string {
-	return []string{
-		"Great quality product, exactly as described. Highly recommend!",
-		"Fast shipping and excellent customer service. Very satisfied.",
-		"Perfect for my needs, works exactly as expected.",
-		"Good value for money, would buy again.",
-	}
-}
-
-// generateSampleNegativeReviews creates sample negative reviews for demo  
-func (s *ScraperService) generateSampleNegativeReviews() []string {
-	return []string{
-		"Product quality could be better for the price.",
-		"Shipping took longer than expected.",
-		"Instructions were not very clear.",
-	}
-}
-
-// parseSearchResults parses Amazon search results
-func (s *ScraperService) parseSearchResults(html string) ([]*AmazonProduct, error) {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse search HTML: %w", err)
+		}()
 	}
 
-	var products []*AmazonProduct
-
-	doc.Find("[data-component-type='s-search-result']").Each(func(i int, sel *goquery.Selection) {
-		product := &AmazonProduct{
-			ScrapedAt: time.Now().UTC().Format(time.RFC3339),
-		}
-
-		// Extract ASIN
-		if asin, exists := sel.Attr("data-asin"); exists {
-			product.ASIN = asin
+	go func() {
+		for _, u := range urls {
+			jobs <- u
 		}
+		close(jobs)
+	}()
 
-		// Extract title
-		titleEl := sel.Find("h2 a span")
-		product.Title = strings.TrimSpace(titleEl.Text())
-
-		// Extract URL
-		if href, exists := sel.Find("h2 a").Attr("href"); exists {
-			product.URL = "https://www.amazon.com" + href
-		}
-
-		// Extract price
-		priceText := sel.Find(".a-price .a-offscreen").First().Text()
-		if price, err := s.parsePrice(priceText); err == nil {
-			product.Price = price
-		}
-
-		// Extract rating
-		ratingText := sel.Find(".a-icon-alt").First().Text()
-		if rating, err := s.parseRating(ratingText); err == nil {
-			product.Rating = rating
-		}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
 
-		// Extract image
-		if imgSrc, exists := sel.Find("img").Attr("src"); exists {
-			product.Images = []string{imgSrc}
-		}
-
-		if product.ASIN != "" && product.Title != "" {
-			products = append(products, product)
-		}
-	})
-
-	return products, nil
-}
-
-// Helper methods
-func (s *ScraperService) setHeaders(req *http.Request) {
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Set("Accept-Encoding", "gzip, deflate")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Upgrade-Insecure-Requests", "1")
-}
-
-func (s *ScraperService) parsePrice(priceText string) (float64, error) {
-	re := regexp.MustCompile(`[\d,]+\.?\d*`)
-	match := re.FindString(priceText)
-	if match == "" {
-		return 0, fmt.Errorf("no price found")
+	for outcome := range results {
+		onResult(outcome.product, outcome.failed)
 	}
-	match = strings.ReplaceAll(match, ",", "")
-	return strconv.ParseFloat(match, 64)
 }
 
-func (s *ScraperService) parseRating(ratingText string) (float64, error) {
-	re := regexp.MustCompile(`(\d+\.?\d*) out of`)
-	matches := re.FindStringSubmatch(ratingText)
-	if len(matches) < 2 {
-		return 0, fmt.Errorf("no rating found")
-	}
-	return strconv.ParseFloat(matches[1], 64)
+// SearchProducts searches Amazon's US storefront for products.
+func (s *ScraperService) SearchProducts(requestID, query string, page int) ([]*AmazonProduct, error) {
+	return s.shops.Search(requestID, "amazon.com", query, SearchOptions{Page: page})
 }
-
-func (s *ScraperService) parseReviewCount(reviewText string) (int, error) {
-	re := regexp.MustCompile(`([\d,]+) ratings`)
-	matches := re.FindStringSubmatch(reviewText)
-	if len(matches) < 2 {
-		return 0, fmt.Errorf("no review count found")
-	}
-	countStr := strings.ReplaceAll(matches[1], ",", "")
-	return strconv.Atoi(countStr)
-} 
\ No newline at end of file
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// SearchOptions narrows a Shop.Search call. Not every shop honors every
+// field; a shop with no concept of category, for instance, simply ignores it.
+type SearchOptions struct {
+	Page     int
+	Category string
+	MinPrice int
+	MaxPrice int
+	Rating   int
+}
+
+// Shop scrapes product and search data from a single marketplace (an Amazon
+// storefront, a third-party store, ...). ShopManager dispatches a product
+// URL to the Shop registered for its hostname, so handlers never need to
+// know which backend served a given request.
+type Shop interface {
+	// Domains lists the hostnames this Shop handles, e.g. "amazon.com" and
+	// "www.amazon.com".
+	Domains() []string
+
+	// Get fetches and parses a single product page. requestID, when
+	// non-empty, is echoed on the outbound fetch as X-Request-ID. opts
+	// customizes the scrape, e.g. WithReviews to also crawl the product's
+	// review pages; a Shop with no use for a given option just ignores it.
+	Get(requestID string, u *url.URL, opts ...ScrapeOption) (*AmazonProduct, error)
+
+	// Search runs a keyword search against the shop.
+	Search(requestID, query string, opts SearchOptions) ([]*AmazonProduct, error)
+}
+
+// ShopManager dispatches scraping calls to the Shop registered for a URL's
+// host, falling back to a generic backend for unregistered hosts if one was
+// set via RegisterFallback.
+type ShopManager struct {
+	mu       sync.RWMutex
+	shops    map[string]Shop // hostname -> Shop
+	fallback Shop
+}
+
+// NewShopManager creates an empty ShopManager; register backends with
+// Register and RegisterFallback before use.
+func NewShopManager() *ShopManager {
+	return &ShopManager{shops: make(map[string]Shop)}
+}
+
+// Register associates shop with every hostname in domains, so Retrieve and
+// Search can dispatch a matching URL or host to it.
+func (m *ShopManager) Register(domains []string, shop Shop) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, domain := range domains {
+		m.shops[strings.ToLower(domain)] = shop
+	}
+}
+
+// RegisterFallback sets the Shop used for hosts with no specific
+// registration, e.g. a generic storefront-platform scraper.
+func (m *ShopManager) RegisterFallback(shop Shop) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fallback = shop
+}
+
+// Retrieve parses rawURL and fetches its product via the Shop registered for
+// its host.
+func (m *ShopManager) Retrieve(requestID, rawURL string, opts ...ScrapeOption) (*AmazonProduct, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid product URL: %q", rawURL)
+	}
+
+	shop, err := m.lookup(u.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	return shop.Get(requestID, u, opts...)
+}
+
+// Search runs query against the Shop registered for host.
+func (m *ShopManager) Search(requestID, host, query string, opts SearchOptions) ([]*AmazonProduct, error) {
+	shop, err := m.lookup(host)
+	if err != nil {
+		return nil, err
+	}
+
+	return shop.Search(requestID, query, opts)
+}
+
+func (m *ShopManager) lookup(host string) (Shop, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	host = strings.ToLower(strings.TrimPrefix(host, "www."))
+	if shop, ok := m.shops[host]; ok {
+		return shop, nil
+	}
+	if shop, ok := m.shops["www."+host]; ok {
+		return shop, nil
+	}
+	if m.fallback != nil {
+		return m.fallback, nil
+	}
+
+	return nil, fmt.Errorf("no shop registered for host %q", host)
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// amazonRegion holds the per-storefront settings for an Amazon TLD: the
+// currency its prices are quoted in and the base URL used to resolve
+// relative search-result links. The page structure itself is the same
+// across regions, so AmazonShop needs nothing more than this to adapt.
+type amazonRegion struct {
+	domain   string
+	currency string
+	baseURL  string
+}
+
+// amazonRegions lists every Amazon storefront this crawler supports.
+var amazonRegions = []amazonRegion{
+	{domain: "amazon.com", currency: "USD", baseURL: "https://www.amazon.com"},
+	{domain: "amazon.es", currency: "EUR", baseURL: "https://www.amazon.es"},
+	{domain: "amazon.de", currency: "EUR", baseURL: "https://www.amazon.de"},
+	{domain: "amazon.co.uk", currency: "GBP", baseURL: "https://www.amazon.co.uk"},
+}
+
+// AmazonShop scrapes a single Amazon storefront. The selectors are shared
+// across regions; only currency and baseURL vary per TLD.
+type AmazonShop struct {
+	fetcher Fetcher
+	region  amazonRegion
+}
+
+// NewAmazonShop creates the Shop for a given Amazon storefront region.
+func NewAmazonShop(fetcher Fetcher, region amazonRegion) *AmazonShop {
+	return &AmazonShop{fetcher: fetcher, region: region}
+}
+
+// Domains returns the bare and "www."-prefixed hostnames for this region.
+func (s *AmazonShop) Domains() []string {
+	return []string{s.region.domain, "www." + s.region.domain}
+}
+
+// productSelector is the element whose presence distinguishes a real Amazon
+// product page from a CAPTCHA wall or an incomplete render.
+const productSelector = "#productTitle"
+
+// searchResultSelector is the element whose presence distinguishes real
+// Amazon search results from a CAPTCHA wall or an incomplete render.
+const searchResultSelector = "[data-component-type='s-search-result']"
+
+// Get fetches and parses a single Amazon product page. With WithReviews
+// set, it also walks the product's /product-reviews/ pages for real
+// positive and critical reviews; without it, PositiveReviews/
+// NegativeReviews are left empty rather than filled with sample text.
+func (s *AmazonShop) Get(requestID string, u *url.URL, opts ...ScrapeOption) (*AmazonProduct, error) {
+	body, err := s.fetcher.Fetch(requestID, u.String(), FetchOptions{RequireSelector: productSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.parseProductPage(body, u.String())
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := applyScrapeOptions(opts)
+	if cfg.maxReviewPages > 0 && product.ASIN != "" {
+		positive, negative, err := NewReviewScraper(s.fetcher).Scrape(requestID, s.region.baseURL, product.ASIN, cfg.maxReviewPages)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scrape reviews: %w", err)
+		}
+		product.PositiveReviews = reviewTexts(positive)
+		product.NegativeReviews = reviewTexts(negative)
+	}
+
+	return product, nil
+}
+
+// Search runs a keyword search against this Amazon storefront.
+func (s *AmazonShop) Search(requestID, query string, opts SearchOptions) ([]*AmazonProduct, error) {
+	page := opts.Page
+	if page <= 0 {
+		page = 1
+	}
+
+	searchURL := fmt.Sprintf("%s/s?k=%s&page=%d", s.region.baseURL,
+		strings.ReplaceAll(query, " ", "+"), page)
+
+	body, err := s.fetcher.Fetch(requestID, searchURL, FetchOptions{RequireSelector: searchResultSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.parseSearchResults(body)
+}
+
+// parseProductPage parses an Amazon product page into an AmazonProduct.
+func (s *AmazonShop) parseProductPage(html, pageURL string) (*AmazonProduct, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	product := &AmazonProduct{
+		URL:       pageURL,
+		Currency:  s.region.currency,
+		ScrapedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	// Extract ASIN from URL
+	asinRegex := regexp.MustCompile(`/dp/([A-Z0-9]{10})`)
+	if matches := asinRegex.FindStringSubmatch(pageURL); len(matches) > 1 {
+		product.ASIN = matches[1]
+	}
+
+	// Extract title
+	product.Title = strings.TrimSpace(doc.Find("#productTitle").Text())
+
+	// Extract price
+	priceText := doc.Find(".a-price-whole").First().Text()
+	if priceText == "" {
+		priceText = doc.Find(".a-price .a-offscreen").First().Text()
+	}
+	if price, err := parsePrice(priceText); err == nil {
+		product.Price = price
+	}
+
+	// Extract rating
+	ratingText := doc.Find(".a-icon-alt").First().Text()
+	if rating, err := parseRating(ratingText); err == nil {
+		product.Rating = rating
+	}
+
+	// Extract review count
+	reviewText := doc.Find("#acrCustomerReviewText").Text()
+	if count, err := parseReviewCount(reviewText); err == nil {
+		product.ReviewCount = count
+	}
+
+	// Extract images
+	doc.Find("#landingImage").Each(func(i int, sel *goquery.Selection) {
+		if src, exists := sel.Attr("src"); exists {
+			product.Images = append(product.Images, src)
+		}
+	})
+
+	// Extract description
+	product.Description = strings.TrimSpace(doc.Find("#feature-bullets ul").Text())
+
+	// Extract features from feature bullets
+	doc.Find("#feature-bullets ul li span").Each(func(i int, sel *goquery.Selection) {
+		feature := strings.TrimSpace(sel.Text())
+		if feature != "" && !strings.Contains(feature, "Make sure") {
+			product.Features = append(product.Features, feature)
+		}
+	})
+
+	// Extract availability
+	product.Availability = strings.TrimSpace(doc.Find("#availability span").Text())
+
+	// Extract brand
+	product.Brand = strings.TrimSpace(doc.Find("#bylineInfo").Text())
+
+	return product, nil
+}
+
+// parseSearchResults parses an Amazon search-results page.
+func (s *AmazonShop) parseSearchResults(html string) ([]*AmazonProduct, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse search HTML: %w", err)
+	}
+
+	var products []*AmazonProduct
+
+	doc.Find("[data-component-type='s-search-result']").Each(func(i int, sel *goquery.Selection) {
+		product := &AmazonProduct{
+			Currency:  s.region.currency,
+			ScrapedAt: time.Now().UTC().Format(time.RFC3339),
+		}
+
+		// Extract ASIN
+		if asin, exists := sel.Attr("data-asin"); exists {
+			product.ASIN = asin
+		}
+
+		// Extract title
+		titleEl := sel.Find("h2 a span")
+		product.Title = strings.TrimSpace(titleEl.Text())
+
+		// Extract URL
+		if href, exists := sel.Find("h2 a").Attr("href"); exists {
+			product.URL = s.region.baseURL + href
+		}
+
+		// Extract price
+		priceText := sel.Find(".a-price .a-offscreen").First().Text()
+		if price, err := parsePrice(priceText); err == nil {
+			product.Price = price
+		}
+
+		// Extract rating
+		ratingText := sel.Find(".a-icon-alt").First().Text()
+		if rating, err := parseRating(ratingText); err == nil {
+			product.Rating = rating
+		}
+
+		// Extract image
+		if imgSrc, exists := sel.Find("img").Attr("src"); exists {
+			product.Images = []string{imgSrc}
+		}
+
+		if product.ASIN != "" && product.Title != "" {
+			products = append(products, product)
+		}
+	})
+
+	return products, nil
+}
+
+// scraperHeaders builds the realistic browser headers used to avoid
+// detection, plus X-Request-ID (when requestID is non-empty) so outbound
+// fetches can be correlated with the inbound API request that triggered
+// them. Shared by every clients.Client built for a Fetch call.
+func scraperHeaders(requestID string) http.Header {
+	h := http.Header{}
+	h.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	h.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	h.Set("Accept-Language", "en-US,en;q=0.5")
+	h.Set("Accept-Encoding", "gzip, deflate")
+	h.Set("Connection", "keep-alive")
+	h.Set("Upgrade-Insecure-Requests", "1")
+	if requestID != "" {
+		h.Set("X-Request-ID", requestID)
+	}
+	return h
+}
+
+func parsePrice(priceText string) (float64, error) {
+	re := regexp.MustCompile(`[\d,]+\.?\d*`)
+	match := re.FindString(priceText)
+	if match == "" {
+		return 0, fmt.Errorf("no price found")
+	}
+	match = strings.ReplaceAll(match, ",", "")
+	return strconv.ParseFloat(match, 64)
+}
+
+func parseRating(ratingText string) (float64, error) {
+	re := regexp.MustCompile(`(\d+\.?\d*) out of`)
+	matches := re.FindStringSubmatch(ratingText)
+	if len(matches) < 2 {
+		return 0, fmt.Errorf("no rating found")
+	}
+	return strconv.ParseFloat(matches[1], 64)
+}
+
+func parseReviewCount(reviewText string) (int, error) {
+	re := regexp.MustCompile(`([\d,]+) ratings`)
+	matches := re.FindStringSubmatch(reviewText)
+	if len(matches) < 2 {
+		return 0, fmt.Errorf("no review count found")
+	}
+	countStr := strings.ReplaceAll(matches[1], ",", "")
+	return strconv.Atoi(countStr)
+}
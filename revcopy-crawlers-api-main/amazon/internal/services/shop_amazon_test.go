@@ -0,0 +1,92 @@
+package services
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/revcopy/crawlers/amazon/internal/clients"
+)
+
+// newTestAmazonShop builds an AmazonShop that fetches through MockClient, so
+// parseProductPage/parseSearchResults run against the checked-in golden
+// fixtures instead of the network.
+func newTestAmazonShop() *AmazonShop {
+	return NewAmazonShop(NewHTTPFetcherWithClient(clients.NewMockClient()), amazonRegions[0])
+}
+
+func TestAmazonShopGetParsesProductPageFixture(t *testing.T) {
+	shop := newTestAmazonShop()
+
+	u, err := url.Parse("https://www.amazon.com/dp/B000000000")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	product, err := shop.Get("test-request", u)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if product.Title != "Wireless Mechanical Keyboard" {
+		t.Errorf("Title = %q, want %q", product.Title, "Wireless Mechanical Keyboard")
+	}
+	if product.Price != 49.99 {
+		t.Errorf("Price = %v, want 49.99", product.Price)
+	}
+	if product.Currency != "USD" {
+		t.Errorf("Currency = %q, want USD", product.Currency)
+	}
+	if product.Rating != 4.5 {
+		t.Errorf("Rating = %v, want 4.5", product.Rating)
+	}
+	if product.ReviewCount != 1204 {
+		t.Errorf("ReviewCount = %d, want 1204", product.ReviewCount)
+	}
+	if product.ASIN != "B000000000" {
+		t.Errorf("ASIN = %q, want B000000000", product.ASIN)
+	}
+	if product.Availability != "In Stock" {
+		t.Errorf("Availability = %q, want %q", product.Availability, "In Stock")
+	}
+	if product.Brand != "Visit the ExampleBrand Store" {
+		t.Errorf("Brand = %q, want %q", product.Brand, "Visit the ExampleBrand Store")
+	}
+
+	wantImages := []string{"https://example.com/images/keyboard.jpg"}
+	if len(product.Images) != len(wantImages) || product.Images[0] != wantImages[0] {
+		t.Errorf("Images = %v, want %v", product.Images, wantImages)
+	}
+
+	wantFeatures := []string{"Hot-swappable switches", "USB-C connector"}
+	if len(product.Features) != len(wantFeatures) {
+		t.Fatalf("Features = %v, want %v", product.Features, wantFeatures)
+	}
+	for i, want := range wantFeatures {
+		if product.Features[i] != want {
+			t.Errorf("Features[%d] = %q, want %q", i, product.Features[i], want)
+		}
+	}
+}
+
+func TestParsePriceRatingReviewCount(t *testing.T) {
+	if price, err := parsePrice("49.99"); err != nil || price != 49.99 {
+		t.Errorf("parsePrice(%q) = %v, %v; want 49.99, nil", "49.99", price, err)
+	}
+	if _, err := parsePrice(""); err == nil {
+		t.Error("parsePrice(\"\") should return an error")
+	}
+
+	if rating, err := parseRating("4.5 out of 5 stars"); err != nil || rating != 4.5 {
+		t.Errorf("parseRating(...) = %v, %v; want 4.5, nil", rating, err)
+	}
+	if _, err := parseRating("no rating here"); err == nil {
+		t.Error("parseRating with no match should return an error")
+	}
+
+	if count, err := parseReviewCount("1,204 ratings"); err != nil || count != 1204 {
+		t.Errorf("parseReviewCount(...) = %v, %v; want 1204, nil", count, err)
+	}
+	if _, err := parseReviewCount("no reviews here"); err == nil {
+		t.Error("parseReviewCount with no match should return an error")
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// steamAppSelector is the element whose presence distinguishes a real Steam
+// store page from a CAPTCHA wall or an incomplete render.
+const steamAppSelector = "#appHubAppName"
+
+// SteamShop scrapes store pages from the Steam storefront.
+type SteamShop struct {
+	fetcher Fetcher
+}
+
+// NewSteamShop creates the Shop for store.steampowered.com.
+func NewSteamShop(fetcher Fetcher) *SteamShop {
+	return &SteamShop{fetcher: fetcher}
+}
+
+func (s *SteamShop) Domains() []string { return []string{"store.steampowered.com"} }
+
+// Get fetches and parses a single Steam store page. opts is ignored: Steam
+// has no review-crawling support.
+func (s *SteamShop) Get(requestID string, u *url.URL, opts ...ScrapeOption) (*AmazonProduct, error) {
+	body, err := s.fetcher.Fetch(requestID, u.String(), FetchOptions{RequireSelector: steamAppSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	product := &AmazonProduct{
+		URL:       u.String(),
+		Currency:  "USD",
+		ScrapedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if appID := steamAppIDFromURL(u); appID != "" {
+		product.ASIN = appID
+	}
+
+	product.Title = strings.TrimSpace(doc.Find("#appHubAppName").Text())
+	product.Description = strings.TrimSpace(doc.Find(".game_description_snippet").Text())
+	product.Brand = strings.TrimSpace(doc.Find(".dev_row .summary a").First().Text())
+
+	priceText := doc.Find(".game_purchase_price").First().Text()
+	if priceText == "" {
+		priceText = doc.Find(".discount_final_price").First().Text()
+	}
+	if price, err := parsePrice(priceText); err == nil {
+		product.Price = price
+	}
+
+	if src, exists := doc.Find(".game_header_image_full").Attr("src"); exists {
+		product.Images = append(product.Images, src)
+	}
+
+	return product, nil
+}
+
+// Search is unsupported: Steam's store search is a JSON API, not an HTML
+// page this scraper parses, so callers get an explicit error instead of
+// silently empty results.
+func (s *SteamShop) Search(requestID, query string, opts SearchOptions) ([]*AmazonProduct, error) {
+	return nil, fmt.Errorf("search is not supported for steam")
+}
+
+// steamAppIDFromURL extracts the numeric app ID from a
+// store.steampowered.com/app/<id>/... URL.
+func steamAppIDFromURL(u *url.URL) string {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) >= 2 && parts[0] == "app" {
+		return parts[1]
+	}
+	return ""
+}
+
+// GenericShopifyShop is the fallback Shop for storefronts this crawler has
+// no dedicated backend for. It scrapes the embedded Open Graph and product
+// meta tags that most Shopify (and Shopify-like) storefronts render on
+// every product page, rather than per-site CSS selectors.
+type GenericShopifyShop struct {
+	fetcher Fetcher
+}
+
+// NewGenericShopifyShop creates the catch-all fallback Shop.
+func NewGenericShopifyShop(fetcher Fetcher) *GenericShopifyShop {
+	return &GenericShopifyShop{fetcher: fetcher}
+}
+
+// Domains returns none: GenericShopifyShop is only reachable as the
+// ShopManager fallback, never by direct hostname registration.
+func (s *GenericShopifyShop) Domains() []string { return nil }
+
+// Get fetches a product page and reads its Open Graph product metadata.
+// opts is ignored: the generic fallback has no review-crawling support.
+func (s *GenericShopifyShop) Get(requestID string, u *url.URL, opts ...ScrapeOption) (*AmazonProduct, error) {
+	body, err := s.fetcher.Fetch(requestID, u.String(), FetchOptions{RequireSelector: `meta[property="og:title"]`})
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	product := &AmazonProduct{
+		URL:       u.String(),
+		ScrapedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	product.Title = metaContent(doc, "og:title")
+	product.Description = metaContent(doc, "og:description")
+	product.Brand = metaContent(doc, "product:brand")
+	product.Currency = metaContent(doc, "product:price:currency")
+	if product.Currency == "" {
+		product.Currency = "USD"
+	}
+	if image := metaContent(doc, "og:image"); image != "" {
+		product.Images = append(product.Images, image)
+	}
+	if priceText := metaContent(doc, "product:price:amount"); priceText != "" {
+		if price, err := strconv.ParseFloat(priceText, 64); err == nil {
+			product.Price = price
+		}
+	}
+	product.Availability = metaContent(doc, "product:availability")
+
+	if product.Title == "" {
+		return nil, fmt.Errorf("no product metadata found at %s", u.String())
+	}
+
+	return product, nil
+}
+
+// Search is unsupported: an arbitrary storefront has no predictable search
+// URL this scraper can construct.
+func (s *GenericShopifyShop) Search(requestID, query string, opts SearchOptions) ([]*AmazonProduct, error) {
+	return nil, fmt.Errorf("search is not supported for this shop")
+}
+
+// metaContent reads an Open Graph / product meta tag's content attribute.
+func metaContent(doc *goquery.Document, property string) string {
+	content, _ := doc.Find(fmt.Sprintf(`meta[property="%s"]`, property)).Attr("content")
+	return strings.TrimSpace(content)
+}
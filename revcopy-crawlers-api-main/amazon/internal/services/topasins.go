@@ -0,0 +1,195 @@
+package services
+
+import (
+	"container/heap"
+	"hash/fnv"
+)
+
+const (
+	cmsRows  = 4    // independent hash rows for the count-min sketch
+	cmsWidth = 2048 // counters per row
+)
+
+// ASINCount is a single entry in the top-K ASINs result.
+type ASINCount struct {
+	ASIN          string `json:"asin"`
+	EstimatedCount uint32 `json:"estimated_count"`
+}
+
+// countMinSketch estimates item frequencies in a fixed amount of memory,
+// trading a small amount of over-counting (never under-counting) for O(1)
+// space independent of the number of distinct items seen.
+type countMinSketch struct {
+	counters [cmsRows][cmsWidth]uint32
+	seeds    [cmsRows]uint32
+}
+
+func newCountMinSketch() *countMinSketch {
+	return &countMinSketch{
+		// Fixed, independent seeds: enough for 4 rows to decorrelate collisions.
+		seeds: [cmsRows]uint32{0x9e3779b9, 0x85ebca6b, 0xc2b2ae35, 0x27d4eb2f},
+	}
+}
+
+func (c *countMinSketch) indexes(item string) [cmsRows]int {
+	var idx [cmsRows]int
+	for row, seed := range c.seeds {
+		h := fnv.New32a()
+		h.Write([]byte{byte(seed), byte(seed >> 8), byte(seed >> 16), byte(seed >> 24)})
+		h.Write([]byte(item))
+		idx[row] = int(h.Sum32() % cmsWidth)
+	}
+	return idx
+}
+
+// Increment records one occurrence of item and returns its new estimated count.
+func (c *countMinSketch) Increment(item string) uint32 {
+	idx := c.indexes(item)
+	for row, col := range idx {
+		c.counters[row][col]++
+	}
+
+	estimate := c.counters[0][idx[0]]
+	for row := 1; row < cmsRows; row++ {
+		if v := c.counters[row][idx[row]]; v < estimate {
+			estimate = v
+		}
+	}
+	return estimate
+}
+
+// Estimate returns the minimum counter across all rows for item, which is
+// the count-min sketch's frequency estimate (always >= the true count).
+func (c *countMinSketch) Estimate(item string) uint32 {
+	idx := c.indexes(item)
+	estimate := c.counters[0][idx[0]]
+	for row := 1; row < cmsRows; row++ {
+		if v := c.counters[row][idx[row]]; v < estimate {
+			estimate = v
+		}
+	}
+	return estimate
+}
+
+// Decay halves every counter in place, so stale items age out over time
+// while frequently-seen items keep a meaningfully large estimate.
+func (c *countMinSketch) Decay() {
+	for row := range c.counters {
+		for col := range c.counters[row] {
+			c.counters[row][col] /= 2
+		}
+	}
+}
+
+// asinHeapItem is one entry in the top-K min-heap.
+type asinHeapItem struct {
+	asin  string
+	count uint32
+	index int // position in the heap slice, maintained by heap.Interface
+}
+
+// asinMinHeap is a min-heap ordered by count, so the root is always the
+// current lowest-count member of the top-K set — the first candidate to
+// evict when a higher-frequency ASIN needs a slot.
+type asinMinHeap []*asinHeapItem
+
+func (h asinMinHeap) Len() int            { return len(h) }
+func (h asinMinHeap) Less(i, j int) bool  { return h[i].count < h[j].count }
+func (h asinMinHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *asinMinHeap) Push(x interface{}) {
+	item := x.(*asinHeapItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *asinMinHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// topASINTracker maintains heavy-hitter ASINs using a count-min sketch for
+// frequency estimation combined with a bounded min-heap of the current
+// top-K candidates.
+type topASINTracker struct {
+	k       int
+	sketch  *countMinSketch
+	items   asinMinHeap
+	byASIN  map[string]*asinHeapItem
+}
+
+func newTopASINTracker(k int) *topASINTracker {
+	return &topASINTracker{
+		k:      k,
+		sketch: newCountMinSketch(),
+		byASIN: make(map[string]*asinHeapItem),
+	}
+}
+
+// Record increments asin's estimated count and updates the top-K heap.
+func (t *topASINTracker) Record(asin string) {
+	estimate := t.sketch.Increment(asin)
+
+	if item, ok := t.byASIN[asin]; ok {
+		item.count = estimate
+		heap.Fix(&t.items, item.index)
+		return
+	}
+
+	if len(t.items) < t.k {
+		item := &asinHeapItem{asin: asin, count: estimate}
+		heap.Push(&t.items, item)
+		t.byASIN[asin] = item
+		return
+	}
+
+	// Heap is full: only displace the current minimum if asin's estimate beats it.
+	if len(t.items) > 0 && estimate > t.items[0].count {
+		evicted := t.items[0]
+		delete(t.byASIN, evicted.asin)
+
+		evicted.asin = asin
+		evicted.count = estimate
+		heap.Fix(&t.items, 0)
+		t.byASIN[asin] = evicted
+	}
+}
+
+// Decay halves the sketch's counters and the heap's cached estimates so
+// stale ASINs fall out of the top-K over time.
+func (t *topASINTracker) Decay() {
+	t.sketch.Decay()
+	for _, item := range t.items {
+		item.count = t.sketch.Estimate(item.asin)
+	}
+	heap.Init(&t.items)
+}
+
+// Top returns up to k ASINs sorted by estimated count, descending.
+func (t *topASINTracker) Top(k int) []ASINCount {
+	sorted := make(asinMinHeap, len(t.items))
+	copy(sorted, t.items)
+
+	// asinMinHeap.Less sorts ascending; reverse to report highest-count first.
+	result := make([]ASINCount, 0, k)
+	for len(sorted) > 0 && len(result) < k {
+		maxIdx := 0
+		for i := 1; i < len(sorted); i++ {
+			if sorted[i].count > sorted[maxIdx].count {
+				maxIdx = i
+			}
+		}
+		result = append(result, ASINCount{ASIN: sorted[maxIdx].asin, EstimatedCount: sorted[maxIdx].count})
+		sorted = append(sorted[:maxIdx], sorted[maxIdx+1:]...)
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"sync"
+)
+
+// UserRepository persists User records. AuthService hashes passwords with
+// bcrypt before calling Create/Update; implementations store whatever hash
+// they're given and never see a plaintext password.
+type UserRepository interface {
+	Get(username string) (*User, error)
+	List() ([]*User, error)
+	Create(user *User) error
+	Update(user *User) error
+	Delete(username string) error
+	FindByOAuthSubject(subject string) (*User, error)
+}
+
+// InMemoryUserRepository is the default UserRepository, holding users in a
+// map for the lifetime of the process. Use PostgresUserRepository for a
+// deployment that needs to survive restarts.
+type InMemoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[string]*User
+}
+
+// NewInMemoryUserRepository creates an empty in-memory user repository.
+func NewInMemoryUserRepository() *InMemoryUserRepository {
+	return &InMemoryUserRepository{users: make(map[string]*User)}
+}
+
+func (r *InMemoryUserRepository) Get(username string) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	user, exists := r.users[username]
+	if !exists {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func (r *InMemoryUserRepository) FindByOAuthSubject(subject string) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, user := range r.users {
+		if user.OAuthSubject != "" && user.OAuthSubject == subject {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (r *InMemoryUserRepository) List() ([]*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	users := make([]*User, 0, len(r.users))
+	for _, user := range r.users {
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (r *InMemoryUserRepository) Create(user *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.users[user.Username]; exists {
+		return errors.New("user already exists")
+	}
+	r.users[user.Username] = user
+	return nil
+}
+
+func (r *InMemoryUserRepository) Update(user *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.users[user.Username]; !exists {
+		return errors.New("user not found")
+	}
+	r.users[user.Username] = user
+	return nil
+}
+
+func (r *InMemoryUserRepository) Delete(username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.users[username]; !exists {
+		return errors.New("user not found")
+	}
+	delete(r.users, username)
+	return nil
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,10 +12,14 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	_ "github.com/revcopy/crawlers/amazon/docs"
 	"github.com/revcopy/crawlers/amazon/internal/config"
+	"github.com/revcopy/crawlers/amazon/internal/grpcserver"
 	"github.com/revcopy/crawlers/amazon/internal/handlers"
 	"github.com/revcopy/crawlers/amazon/internal/middleware"
+	"github.com/revcopy/crawlers/amazon/internal/oauth"
 	"github.com/revcopy/crawlers/amazon/internal/services"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	swaggerFiles "github.com/swaggo/files"
@@ -42,15 +48,84 @@ import (
 func main() {
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
 	// Initialize services
-	proxyService := services.NewProxyService()
-	scraperService := services.NewScraperService(proxyService)
-	authService := services.NewAuthService(cfg.JWTSecret)
+	proxyStore, err := newProxyStore(cfg.Proxy)
+	if err != nil {
+		log.Fatalf("Failed to open proxy store: %v", err)
+	}
+
+	var proxySeeds []services.ProxySeedConfig
+	if cfg.Proxy.Host != "" {
+		proxySeeds = append(proxySeeds, services.ProxySeedConfig{
+			Username: cfg.Proxy.Username,
+			Password: cfg.Proxy.Password,
+			Host:     cfg.Proxy.Host,
+			Port:     cfg.Proxy.Port,
+		})
+	}
+	for _, seed := range cfg.Proxy.SeedList {
+		proxySeeds = append(proxySeeds, services.ProxySeedConfig{
+			Username: seed.Username,
+			Password: seed.Password,
+			Host:     seed.Host,
+			Port:     seed.Port,
+		})
+	}
+	proxyService := services.NewProxyService(proxyStore, proxySeeds, cfg.Proxy.FilePath)
+	if cfg.Proxy.FileWatch {
+		if err := proxyService.WatchProxiesFile(); err != nil {
+			log.Printf("Failed to watch proxies config file: %v", err)
+		}
+	}
+	proxyService.SetHealthCheckConfig(services.HealthCheckConfig{
+		Interval:    cfg.Proxy.HealthCheck.Interval,
+		CheckURL:    cfg.Proxy.HealthCheck.CheckURL,
+		CanaryURL:   cfg.Proxy.HealthCheck.CanaryURL,
+		Timeout:     cfg.Proxy.HealthCheck.Timeout,
+		MaxFailures: cfg.Proxy.HealthCheck.MaxFailures,
+		BanFailures: cfg.Proxy.HealthCheck.BanFailures,
+		MaxBackoff:  cfg.Proxy.HealthCheck.MaxBackoff,
+	})
+	proxyService.SetBypassDomains(cfg.Proxy.BypassDomains)
+	scraperService := services.NewScraperService(proxyService, cfg.Renderer, cfg.BulkScrape)
+	userRepo, err := newUserRepository(cfg.Auth)
+	if err != nil {
+		log.Fatalf("Failed to initialize user store: %v", err)
+	}
+	authService := services.NewAuthService(cfg.JWTSecret, services.NewInMemoryTokenStore(), userRepo, cfg.Auth.BcryptCost)
+	authService.SetOAuthRoleMapping(cfg.OAuth.RoleMapping)
 	analyticsService := services.NewAnalyticsService()
+	proxyService.SetAnalyticsService(analyticsService)
+	oauthProviders := newOAuthRegistry(cfg.OAuth)
+	oauthStates, err := newOAuthStateStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize OAuth state store: %v", err)
+	}
+
+	productStore, err := newProductStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize product cache store: %v", err)
+	}
+	scraperService.SetProductStore(productStore, cfg.ProductCache.TTL, cfg.ProductCache.RefreshWorkers)
+
+	marketplaceRegistry := newMarketplaceRegistry(scraperService, cfg.Marketplace)
+
+	rateLimitStore, err := newRateLimitStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize rate limit store: %v", err)
+	}
+	rateLimitKeyOpts := middleware.KeyOptions{
+		IPv4MaskBits:   cfg.RateLimit.IPv4MaskBits,
+		IPv6MaskBits:   cfg.RateLimit.IPv6MaskBits,
+		TrustedProxies: middleware.ParseTrustedProxies(cfg.RateLimit.TrustedProxies),
+	}
 
 	// Initialize handlers
-	h := handlers.New(scraperService, authService, analyticsService, proxyService)
+	h := handlers.New(scraperService, authService, analyticsService, proxyService, oauthProviders, cfg.OAuth, oauthStates, marketplaceRegistry)
 
 	// Setup Gin router
 	if cfg.Environment == "production" {
@@ -59,9 +134,14 @@ func main() {
 
 	router := gin.New()
 	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Recovery(analyticsService))
 	router.Use(middleware.CORS())
-	router.Use(middleware.RateLimit())
+	router.Use(middleware.RateLimitTier("public", rateLimitStore, middleware.TierOptions{
+		RequestsPerMinute: cfg.RateLimit.Public.RequestsPerMinute,
+		BurstSize:         cfg.RateLimit.Public.BurstSize,
+		Key:               rateLimitKeyOpts,
+	}))
 
 	// Swagger documentation endpoint
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -86,18 +166,32 @@ func main() {
 					"yaml_spec":      "/static/swagger.yaml",
 				},
 				"authentication": gin.H{
-					"login":   "POST /api/v1/auth/login",
-					"refresh": "POST /api/v1/auth/refresh",
+					"login":          "POST /api/v1/auth/login",
+					"refresh":        "POST /api/v1/auth/refresh",
+					"logout":         "POST /api/v1/auth/logout",
+					"oauth_login":    "GET /api/v1/auth/oauth/:provider/login",
+					"oauth_callback": "GET /api/v1/auth/oauth/:provider/callback",
+					"create_token":   "POST /api/v1/auth/tokens",
+					"list_tokens":    "GET /api/v1/auth/tokens",
+					"revoke_token":   "DELETE /api/v1/auth/tokens/:id",
 				},
 				"scraping": gin.H{
 					"single_product": "POST /api/v1/amazon/scrape",
 					"bulk_scrape":    "POST /api/v1/amazon/bulk-scrape",
 					"search":         "POST /api/v1/amazon/search",
 					"get_product":    "GET /api/v1/amazon/product/:asin",
+					"delete_product": "DELETE /api/v1/amazon/product/:asin",
+				},
+				"marketplaces": gin.H{
+					"list":        "GET /api/v1/marketplaces",
+					"scrape":      "POST /api/v1/marketplaces/:name/scrape",
+					"search":      "POST /api/v1/marketplaces/:name/search",
+					"bulk_scrape": "POST /api/v1/marketplaces/:name/bulk-scrape",
 				},
 				"analytics": gin.H{
 					"stats":       "GET /api/v1/analytics/stats",
 					"performance": "GET /api/v1/analytics/performance",
+					"events":      "GET /api/v1/analytics/events",
 					"track_event": "POST /api/v1/analytics/track",
 				},
 				"proxy": gin.H{
@@ -112,19 +206,45 @@ func main() {
 					"update_user":  "PUT /api/v1/users/:username",
 					"delete_user":  "DELETE /api/v1/users/:username",
 					"get_user_proxy": "GET /api/v1/users/:username/proxy",
+					"resolve_user_proxy": "GET /api/v1/users/:username/proxy/resolve",
+				},
+				"role_management": gin.H{
+					"create_role": "POST /api/v1/roles",
+					"list_roles":  "GET /api/v1/roles",
+					"get_role":    "GET /api/v1/roles/:name",
+					"update_role": "PUT /api/v1/roles/:name",
+					"delete_role": "DELETE /api/v1/roles/:name",
 				},
 				"proxy_management": gin.H{
-					"create_proxy":   "POST /api/v1/proxies",
-					"list_proxies":   "GET /api/v1/proxies",
-					"get_proxy":      "GET /api/v1/proxies/:proxy_id",
-					"update_proxy":   "PUT /api/v1/proxies/:proxy_id",
-					"delete_proxy":   "DELETE /api/v1/proxies/:proxy_id",
+					"create_proxy":       "POST /api/v1/proxies",
+					"list_proxies":       "GET /api/v1/proxies",
+					"get_proxy":          "GET /api/v1/proxies/:proxy_id",
+					"update_proxy":       "PUT /api/v1/proxies/:proxy_id",
+					"delete_proxy":       "DELETE /api/v1/proxies/:proxy_id",
+					"get_proxy_health":   "GET /api/v1/proxies/:proxy_id/health",
+					"drain_proxy":        "POST /api/v1/proxies/:proxy_id/drain",
+					"test_proxy_delay":   "GET /api/v1/proxies/:proxy_id/delay",
+					"test_proxies_delay": "POST /api/v1/proxies/delay",
+					"reload_proxies":     "POST /api/v1/proxies/reload",
 				},
 				"proxy_assignments": gin.H{
 					"assign_proxy":     "POST /api/v1/proxy-assignments",
 					"list_assignments": "GET /api/v1/proxy-assignments",
 					"unassign_proxy":   "DELETE /api/v1/proxy-assignments/:username",
 				},
+				"proxy_groups": gin.H{
+					"create_group": "POST /api/v1/proxy-groups",
+					"list_groups":  "GET /api/v1/proxy-groups",
+					"get_group":    "GET /api/v1/proxy-groups/:group_id",
+					"update_group": "PUT /api/v1/proxy-groups/:group_id",
+					"delete_group": "DELETE /api/v1/proxy-groups/:group_id",
+					"select_group": "PUT /api/v1/proxy-groups/:group_id/select",
+				},
+				"proxy_dashboard": gin.H{
+					"list_stats":   "GET /api/v1/proxy-stats",
+					"proxy_stats":  "GET /api/v1/proxy-stats/:proxy_id",
+					"proxy_traffic": "GET /api/v1/proxy-stats/:proxy_id/traffic",
+				},
 			},
 			"default_users": gin.H{
 				"admin":     "admin123",
@@ -134,6 +254,9 @@ func main() {
 		})
 	})
 
+	// Prometheus metrics endpoint
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -146,44 +269,106 @@ func main() {
 	// API routes
 	api := router.Group("/api/v1")
 	{
-		// Authentication
+		// Authentication. Stricter than the default "public" tier applied
+		// above, to blunt credential stuffing against /login.
 		auth := api.Group("/auth")
+		auth.Use(middleware.RateLimitTier("auth", rateLimitStore, middleware.TierOptions{
+			RequestsPerMinute: cfg.RateLimit.Auth.RequestsPerMinute,
+			BurstSize:         cfg.RateLimit.Auth.BurstSize,
+			Key:               rateLimitKeyOpts,
+		}))
 		{
 			auth.POST("/login", h.Login)
 			auth.POST("/refresh", h.RefreshToken)
+			auth.GET("/oauth/:provider/login", h.OAuthLogin)
+			auth.GET("/oauth/:provider/callback", h.OAuthCallback)
 		}
 
 		// Protected routes
 		protected := api.Group("/")
 		protected.Use(middleware.AuthRequired(authService))
+		protected.Use(middleware.RateLimitByUser(rateLimitStore, middleware.PlanTiers{
+			services.PlanFree: {
+				RequestsPerMinute: cfg.RateLimit.UserFree.RequestsPerMinute,
+				BurstSize:         cfg.RateLimit.UserFree.BurstSize,
+			},
+			services.PlanPro: {
+				RequestsPerMinute: cfg.RateLimit.UserPro.RequestsPerMinute,
+				BurstSize:         cfg.RateLimit.UserPro.BurstSize,
+			},
+			services.PlanEnterprise: {
+				RequestsPerMinute: cfg.RateLimit.UserEnterprise.RequestsPerMinute,
+				BurstSize:         cfg.RateLimit.UserEnterprise.BurstSize,
+			},
+		}, services.PlanFree, func(c *gin.Context) string { return c.GetString("plan") }))
 		{
-			// Amazon scraping endpoints
+			// Authenticated session management
+			authProtected := protected.Group("/auth")
+			{
+				authProtected.POST("/logout", h.Logout)
+
+				tokens := authProtected.Group("/tokens")
+				{
+					tokens.POST("/", h.CreateToken)      // Mint a personal access token
+					tokens.GET("/", h.ListTokens)        // List the caller's tokens
+					tokens.DELETE("/:id", h.RevokeToken) // Revoke a token
+				}
+			}
+
+			// Amazon scraping endpoints, on the "heavy_compute" tier since
+			// scraping is far costlier per request than the default tier
+			// assumes.
 			amazon := protected.Group("/amazon")
+			amazon.Use(middleware.RateLimitTier("heavy_compute", rateLimitStore, middleware.TierOptions{
+				RequestsPerMinute: cfg.RateLimit.HeavyCompute.RequestsPerMinute,
+				BurstSize:         cfg.RateLimit.HeavyCompute.BurstSize,
+				Key:               rateLimitKeyOpts,
+			}))
 			{
-				amazon.POST("/scrape", h.ScrapeAmazonProduct)
-				amazon.POST("/bulk-scrape", h.BulkScrapeAmazonProducts)
-				amazon.GET("/product/:asin", h.GetAmazonProduct)
-				amazon.POST("/search", h.SearchAmazonProducts)
+				amazon.POST("/scrape", middleware.RequirePermission(services.PermissionAmazonScrape), h.ScrapeAmazonProduct)
+				amazon.POST("/bulk-scrape", middleware.RequirePermission(services.PermissionAmazonBulk), h.BulkScrapeAmazonProducts)
+				amazon.GET("/product/:asin", middleware.RequirePermission(services.PermissionAmazonScrape), h.GetAmazonProduct)
+				amazon.DELETE("/product/:asin", middleware.RequirePermission(services.PermissionUsersAdmin), h.DeleteAmazonProduct)
+				amazon.POST("/search", middleware.RequirePermission(services.PermissionAmazonScrape), h.SearchAmazonProducts)
+			}
+
+			// Marketplace plugin endpoints: the built-in Amazon backend plus
+			// any plugin registered from cfg.Marketplace.PluginDir, dispatched
+			// by name; /api/v1/amazon/* above stays in place as a thin,
+			// backward-compatible alias for the "amazon" marketplace.
+			marketplaces := protected.Group("/marketplaces")
+			marketplaces.Use(middleware.RateLimitTier("heavy_compute", rateLimitStore, middleware.TierOptions{
+				RequestsPerMinute: cfg.RateLimit.HeavyCompute.RequestsPerMinute,
+				BurstSize:         cfg.RateLimit.HeavyCompute.BurstSize,
+				Key:               rateLimitKeyOpts,
+			}))
+			{
+				marketplaces.GET("/", middleware.RequirePermission(services.PermissionAmazonScrape), h.ListMarketplaces)
+				marketplaces.POST("/:name/scrape", middleware.RequirePermission(services.PermissionAmazonScrape), h.ScrapeMarketplaceProduct)
+				marketplaces.POST("/:name/search", middleware.RequirePermission(services.PermissionAmazonScrape), h.SearchMarketplaceProducts)
+				marketplaces.POST("/:name/bulk-scrape", middleware.RequirePermission(services.PermissionAmazonBulk), h.BulkScrapeMarketplaceProducts)
 			}
 
 			// Analytics endpoints
 			analytics := protected.Group("/analytics")
 			{
-				analytics.GET("/stats", h.GetAnalyticsStats)
-				analytics.GET("/performance", h.GetPerformanceMetrics)
-				analytics.POST("/track", h.TrackEvent)
+				analytics.GET("/stats", middleware.RequirePermission(services.PermissionAnalyticsRead), h.GetAnalyticsStats)
+				analytics.GET("/performance", middleware.RequirePermission(services.PermissionAnalyticsRead), h.GetPerformanceMetrics)
+				analytics.GET("/events", middleware.RequirePermission(services.PermissionAnalyticsRead), h.ListAnalyticsEvents)
+				analytics.POST("/track", middleware.RequirePermission(services.PermissionAnalyticsWrite), h.TrackEvent)
 			}
 
 			// Legacy proxy management (keep for compatibility)
 			proxy := protected.Group("/proxy")
 			{
-				proxy.POST("/configure", h.ConfigureProxy)
-				proxy.GET("/status", h.GetProxyStatus)
-				proxy.POST("/test", h.TestProxy)
+				proxy.POST("/configure", middleware.RequirePermission(services.PermissionProxyWrite), h.ConfigureProxy)
+				proxy.GET("/status", middleware.RequirePermission(services.PermissionProxyRead), h.GetProxyStatus)
+				proxy.POST("/test", middleware.RequirePermission(services.PermissionProxyRead), h.TestProxy)
 			}
 
 			// User management endpoints
 			users := protected.Group("/users")
+			users.Use(middleware.RequirePermission(services.PermissionUsersAdmin))
 			{
 				users.POST("/", h.CreateUser)                     // Create user
 				users.GET("/", h.ListUsers)                       // List all users
@@ -191,24 +376,61 @@ func main() {
 				users.PUT("/:username", h.UpdateUser)             // Update user
 				users.DELETE("/:username", h.DeleteUser)          // Delete user
 				users.GET("/:username/proxy", h.GetUserProxy)     // Get user's assigned proxy
+				users.GET("/:username/proxy/resolve", h.ResolveUserProxy) // Check whether a URL bypasses the user's assigned proxy
+			}
+
+			// Role management endpoints
+			roles := protected.Group("/roles")
+			roles.Use(middleware.RequirePermission(services.PermissionUsersAdmin))
+			{
+				roles.POST("/", h.CreateRole)                     // Create role
+				roles.GET("/", h.ListRoles)                       // List all roles
+				roles.GET("/:name", h.GetRole)                    // Get role by name
+				roles.PUT("/:name", h.UpdateRole)                 // Update role permissions
+				roles.DELETE("/:name", h.DeleteRole)              // Delete role
 			}
 
 			// Enhanced proxy management endpoints
 			proxies := protected.Group("/proxies")
 			{
-				proxies.POST("/", h.CreateProxy)                  // Create proxy
-				proxies.GET("/", h.ListProxies)                   // List all proxies
-				proxies.GET("/:proxy_id", h.GetProxy)             // Get proxy by ID
-				proxies.PUT("/:proxy_id", h.UpdateProxy)          // Update proxy
-				proxies.DELETE("/:proxy_id", h.DeleteProxy)       // Delete proxy
+				proxies.POST("/", middleware.RequirePermission(services.PermissionProxyWrite), h.CreateProxy)
+				proxies.GET("/", middleware.RequirePermission(services.PermissionProxyRead), h.ListProxies)
+				proxies.GET("/:proxy_id", middleware.RequirePermission(services.PermissionProxyRead), h.GetProxy)
+				proxies.PUT("/:proxy_id", middleware.RequirePermission(services.PermissionProxyWrite), h.UpdateProxy)
+				proxies.DELETE("/:proxy_id", middleware.RequirePermission(services.PermissionProxyWrite), h.DeleteProxy)
+				proxies.GET("/:proxy_id/health", middleware.RequirePermission(services.PermissionProxyRead), h.GetProxyHealth)
+				proxies.POST("/:proxy_id/drain", middleware.RequirePermission(services.PermissionProxyWrite), h.DrainProxy)
+				proxies.GET("/:proxy_id/delay", middleware.RequirePermission(services.PermissionProxyRead), h.TestProxyDelay)
+				proxies.POST("/delay", middleware.RequirePermission(services.PermissionProxyRead), h.TestProxyDelayBulk)
+				proxies.POST("/reload", middleware.RequirePermission(services.PermissionProxyWrite), h.ReloadProxies)
 			}
 
 			// Proxy assignment endpoints
 			proxyAssignments := protected.Group("/proxy-assignments")
 			{
-				proxyAssignments.POST("/", h.AssignProxyToUser)           // Assign proxy to user
-				proxyAssignments.GET("/", h.ListUserProxyAssignments)     // List all assignments
-				proxyAssignments.DELETE("/:username", h.UnassignProxyFromUser) // Unassign proxy from user
+				proxyAssignments.POST("/", middleware.RequirePermission(services.PermissionProxyWrite), h.AssignProxyToUser)
+				proxyAssignments.GET("/", middleware.RequirePermission(services.PermissionProxyRead), h.ListUserProxyAssignments)
+				proxyAssignments.DELETE("/:username", middleware.RequirePermission(services.PermissionProxyWrite), h.UnassignProxyFromUser)
+			}
+
+			// Proxy group endpoints (failover/round-robin pools of proxies)
+			proxyGroups := protected.Group("/proxy-groups")
+			{
+				proxyGroups.POST("/", middleware.RequirePermission(services.PermissionProxyWrite), h.CreateProxyGroup)
+				proxyGroups.GET("/", middleware.RequirePermission(services.PermissionProxyRead), h.ListProxyGroups)
+				proxyGroups.GET("/:group_id", middleware.RequirePermission(services.PermissionProxyRead), h.GetProxyGroup)
+				proxyGroups.PUT("/:group_id", middleware.RequirePermission(services.PermissionProxyWrite), h.UpdateProxyGroup)
+				proxyGroups.DELETE("/:group_id", middleware.RequirePermission(services.PermissionProxyWrite), h.DeleteProxyGroup)
+				proxyGroups.PUT("/:group_id/select", middleware.RequirePermission(services.PermissionProxyWrite), h.SelectProxyGroupMember)
+			}
+
+			// Proxy dashboard endpoints (live health + traffic stats)
+			proxyStats := protected.Group("/proxy-stats")
+			proxyStats.Use(middleware.RequirePermission(services.PermissionProxyRead))
+			{
+				proxyStats.GET("/", h.ListProxyDashboard)                  // List all proxies' stats
+				proxyStats.GET("/:proxy_id", h.GetProxyDashboard)          // Single proxy stats
+				proxyStats.GET("/:proxy_id/traffic", h.GetProxyTraffic)    // Proxy traffic-over-time
 			}
 		}
 	}
@@ -230,6 +452,20 @@ func main() {
 		}
 	}()
 
+	// Start the gRPC server alongside the REST API, on its own port, so
+	// CLIs/workers can use either transport against the same services.
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("Failed to listen on gRPC port %s: %v", cfg.GRPCPort, err)
+	}
+	grpcSrv := grpcserver.NewServer(scraperService, authService, analyticsService, proxyService)
+	go func() {
+		log.Printf("Amazon Crawler gRPC API starting on port %s", cfg.GRPCPort)
+		if err := grpcSrv.Serve(grpcListener); err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
 	// Wait for interrupt signal for graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -245,5 +481,105 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	grpcSrv.GracefulStop()
+
 	log.Println("Server exited")
+}
+
+// newOAuthRegistry builds the set of OAuth2/OIDC providers enabled by cfg.
+// Providers without a client ID configured are left out of the registry, so
+// their login/callback routes 404 instead of failing with a client
+// error at request time.
+func newOAuthRegistry(cfg config.OAuthConfig) *oauth.Registry {
+	registry := oauth.NewRegistry()
+
+	if cfg.Google.Enabled() {
+		registry.Register(oauth.NewGoogleProvider(cfg.Google.ClientID, cfg.Google.ClientSecret, cfg.Google.RedirectURL))
+	}
+	if cfg.GitHub.Enabled() {
+		registry.Register(oauth.NewGitHubProvider(cfg.GitHub.ClientID, cfg.GitHub.ClientSecret, cfg.GitHub.RedirectURL))
+	}
+	if cfg.OIDC.Enabled() {
+		registry.Register(oauth.NewOIDCProvider(cfg.OIDC.ClientID, cfg.OIDC.ClientSecret, cfg.OIDC.RedirectURL, cfg.OIDC.IssuerURL, cfg.OIDC.RoleClaim))
+	}
+
+	return registry
+}
+
+// newOAuthStateStore selects the pending-login store for OAuth2/OIDC flows:
+// Redis (via cfg.RedisURL) when cfg.OAuth.StateStoreBackend is "redis", so
+// logins survive across instances behind a load balancer, otherwise an
+// in-memory store for a single-instance deployment.
+func newOAuthStateStore(cfg *config.Config) (oauth.StateStore, error) {
+	if cfg.OAuth.StateStoreBackend == "redis" {
+		return oauth.NewRedisStateStore(cfg.RedisURL)
+	}
+	return oauth.NewMemoryStateStore(), nil
+}
+
+// newUserRepository selects the user store: Postgres when cfg.DatabaseURL is
+// set, so accounts survive restarts, otherwise an in-memory store for local
+// dev.
+func newUserRepository(cfg config.AuthConfig) (services.UserRepository, error) {
+	if cfg.DatabaseURL == "" {
+		return services.NewInMemoryUserRepository(), nil
+	}
+
+	return services.NewPostgresUserRepository(context.Background(), cfg.DatabaseURL)
+}
+
+// newProxyStore selects the persistent proxy store backend: encrypted
+// BoltDB (default) or, when cfg.Proxy.StoreBackend is "file", one plaintext
+// JSON file per proxy under cfg.Proxy.FileStoreDir.
+func newProxyStore(cfg config.ProxyConfig) (services.ProxyStore, error) {
+	if cfg.StoreBackend == "file" {
+		return services.NewJSONFileProxyStore(cfg.FileStoreDir)
+	}
+
+	return services.NewBoltProxyStore(cfg.DBPath, []byte(cfg.EncryptionKey))
+}
+
+// newProductStore selects the GetAmazonProduct cache backend: Postgres when
+// cfg.ProductCache.DatabaseURL is set, otherwise Redis (via cfg.RedisURL).
+func newProductStore(cfg *config.Config) (services.ProductStore, error) {
+	if cfg.ProductCache.DatabaseURL != "" {
+		return services.NewPostgresProductStore(context.Background(), cfg.ProductCache.DatabaseURL)
+	}
+
+	return services.NewRedisProductStore(cfg.RedisURL)
+}
+
+// newRateLimitStore selects the rate limit counter backend: in-memory
+// (default, single instance) or, when cfg.RateLimit.Store is "redis", Redis
+// (via cfg.RedisURL) so every replica behind a load balancer shares the
+// same counters instead of each one granting its own burst.
+func newRateLimitStore(cfg *config.Config) (middleware.Store, error) {
+	if cfg.RateLimit.Store != "redis" {
+		return middleware.NewInMemoryStoreWithLimits(cfg.RateLimit.IdleTTL, cfg.RateLimit.MaxEntries), nil
+	}
+
+	opts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse REDIS_URL: %w", err)
+	}
+
+	return middleware.NewRedisStore(redis.NewClient(opts)), nil
+}
+
+// newMarketplaceRegistry builds the registry /api/v1/marketplaces dispatches
+// against: the built-in "amazon" backend (wrapping scraperService, so
+// /api/v1/amazon/* keeps working unchanged) plus any plugin discovered
+// under cfg.PluginDir.
+func newMarketplaceRegistry(scraperService *services.ScraperService, cfg config.MarketplaceConfig) *services.MarketplaceRegistry {
+	registry := services.NewMarketplaceRegistry()
+
+	registry.Register(services.NewAmazonMarketplace(scraperService), services.MarketplaceManifest{
+		Name:         "amazon",
+		Capabilities: []string{"scrape", "search", "bulk_scrape"},
+		Builtin:      true,
+	})
+
+	services.LoadPluginsFromDir(registry, cfg.PluginDir)
+
+	return registry
 } 
\ No newline at end of file